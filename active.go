@@ -0,0 +1,61 @@
+package gobrew
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/kevincobain2000/gobrew/utils"
+)
+
+// reGoVersionOutput matches `go version` output, e.g. go version go1.17.6 linux/amd64
+var reGoVersionOutput = regexp.MustCompile(`go version go(\S+)`)
+
+// parseGoVersionOutput returns the version reported by `go version`
+func parseGoVersionOutput(output string) (string, error) {
+	m := reGoVersionOutput.FindStringSubmatch(output)
+	if m == nil {
+		return "", fmt.Errorf("unexpected go version output: %q", strings.TrimSpace(output))
+	}
+	return m[1], nil
+}
+
+// DetectActiveGo reports the version of the go found on PATH and whether it
+// is managed by gobrew, i.e. its GOROOT resolves inside versionsDir or the
+// system root. This catches a GOROOT set by hand outside of gobrew.
+func (gb *GoBrew) DetectActiveGo() (version string, managed bool, err error) {
+	output, err := runGo("go", "version")
+	if err != nil {
+		return "", false, fmt.Errorf("go version failed: %s", err)
+	}
+	version, err = parseGoVersionOutput(utils.BytesToString(output))
+	if err != nil {
+		return "", false, err
+	}
+
+	output, err = runGo("go", "env", "GOROOT")
+	if err != nil {
+		return version, false, fmt.Errorf("go env GOROOT failed: %s", err)
+	}
+	goroot := strings.TrimSpace(utils.BytesToString(output))
+	if resolved, err := filepath.EvalSymlinks(goroot); err == nil {
+		goroot = resolved
+	}
+
+	for _, versionsDir := range []string{gb.versionsDir, gb.systemVersionsDir} {
+		if versionsDir == "" {
+			continue
+		}
+		if resolved, err := filepath.EvalSymlinks(versionsDir); err == nil {
+			versionsDir = resolved
+		}
+		rel, err := filepath.Rel(versionsDir, goroot)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		// versionsDir/<version>/go
+		return strings.Split(filepath.ToSlash(rel), "/")[0], true, nil
+	}
+	return version, false, nil
+}
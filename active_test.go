@@ -0,0 +1,46 @@
+package gobrew
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectActiveGo(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	gb.systemVersionsDir = newTestGoBrew(tempDir(t)).versionsDir
+	outside := tempDir(t)
+
+	tests := []struct {
+		name        string
+		goroot      string
+		wantVersion string
+		wantManaged bool
+	}{
+		{name: "managed", goroot: filepath.Join(gb.versionsDir, "1.17.6", "go"), wantVersion: "1.17.6", wantManaged: true},
+		{name: "system root", goroot: filepath.Join(gb.systemVersionsDir, "1.16", "go"), wantVersion: "1.16", wantManaged: true},
+		{name: "manual GOROOT", goroot: outside, wantVersion: "1.16", wantManaged: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeBin(t, "go", `if [ "$1" = "env" ]; then echo "`+tt.goroot+`"; else echo "go version go`+tt.wantVersion+` linux/amd64"; fi
+`)
+			version, managed, err := gb.DetectActiveGo()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if version != tt.wantVersion || managed != tt.wantManaged {
+				t.Errorf("got (%s, %v), want (%s, %v)", version, managed, tt.wantVersion, tt.wantManaged)
+			}
+		})
+	}
+}
+
+func TestParseGoVersionOutput(t *testing.T) {
+	version, err := parseGoVersionOutput("go version go1.18beta1 darwin/arm64\n")
+	if err != nil || version != "1.18beta1" {
+		t.Errorf("got (%s, %v)", version, err)
+	}
+	if _, err := parseGoVersionOutput("bash: go: command not found"); err == nil {
+		t.Error("expected an error for unexpected output")
+	}
+}
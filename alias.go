@@ -0,0 +1,169 @@
+package gobrew
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kevincobain2000/gobrew/utils"
+)
+
+const (
+	aliasesFile = "aliases.json"
+	defaultFile = "default"
+	// defaultAlias is the name Use resolves to the default version
+	defaultAlias = "default"
+)
+
+// Aliases returns the alias names and the versions they point at
+func (gb *GoBrew) Aliases() (map[string]string, error) {
+	aliases := make(map[string]string)
+	content, err := ioutil.ReadFile(filepath.Join(gb.installDir, aliasesFile))
+	if os.IsNotExist(err) {
+		return aliases, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, &aliases); err != nil {
+		return nil, fmt.Errorf("%s is corrupt: %s", aliasesFile, err)
+	}
+	return aliases, nil
+}
+
+// Alias names an installed version, e.g. stable -> 1.17.6
+func (gb *GoBrew) Alias(name string, version string) error {
+	if name == "" || name == defaultAlias {
+		return fmt.Errorf("invalid alias name %q", name)
+	}
+	if !gb.existsVersion(version) {
+		return fmt.Errorf("version %s is not installed", version)
+	}
+	aliases, err := gb.Aliases()
+	if err != nil {
+		return err
+	}
+	aliases[name] = version
+	return gb.writeAliases(aliases)
+}
+
+// Unalias removes the alias name
+func (gb *GoBrew) Unalias(name string) error {
+	aliases, err := gb.Aliases()
+	if err != nil {
+		return err
+	}
+	if _, ok := aliases[name]; !ok {
+		return fmt.Errorf("alias %s does not exist", name)
+	}
+	delete(aliases, name)
+	return gb.writeAliases(aliases)
+}
+
+func (gb *GoBrew) writeAliases(aliases map[string]string) error {
+	content, err := json.MarshalIndent(aliases, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(gb.installDir, os.ModePerm); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(gb.installDir, aliasesFile), content, 0644)
+}
+
+// DefaultVersion returns the version recorded as default, empty when none is
+func (gb *GoBrew) DefaultVersion() (string, error) {
+	content, err := ioutil.ReadFile(filepath.Join(gb.installDir, defaultFile))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(utils.BytesToString(content)), nil
+}
+
+// SetDefault records an installed version as the default
+func (gb *GoBrew) SetDefault(version string) error {
+	if !gb.existsVersion(version) {
+		return fmt.Errorf("version %s is not installed", version)
+	}
+	if err := os.MkdirAll(gb.installDir, os.ModePerm); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(gb.installDir, defaultFile), []byte(version+"\n"), 0644)
+}
+
+// resolveAlias returns the version an alias or "default" points at, or name
+// itself when it is neither
+func (gb *GoBrew) resolveAlias(name string) string {
+	if name == defaultAlias {
+		if version, err := gb.DefaultVersion(); err == nil && version != "" {
+			return version
+		}
+		return name
+	}
+	aliases, err := gb.Aliases()
+	if err != nil {
+		return name
+	}
+	if version, ok := aliases[name]; ok {
+		return version
+	}
+	return name
+}
+
+// dropReferences runs cleanReferences for an uninstalled version, reporting
+// each reference removed
+func (gb *GoBrew) dropReferences(version string) {
+	cleaned, err := gb.cleanReferences(version)
+	for _, ref := range cleaned {
+		utils.ColorInfo.Fprintf(gb.stdout, "[Info] Removed %s pointing at %s\n", ref, version)
+	}
+	if err != nil {
+		utils.ColorError.Fprintf(gb.stderr, "[Error] Could not clean references to %s: %s\n", version, err)
+	}
+}
+
+// cleanReferences removes the aliases and the default pointing at version,
+// returning a description of each reference removed
+func (gb *GoBrew) cleanReferences(version string) ([]string, error) {
+	cleaned := make([]string, 0)
+
+	aliases, err := gb.Aliases()
+	if err != nil {
+		return cleaned, err
+	}
+	names := make([]string, 0)
+	for name, target := range aliases {
+		if target == version {
+			names = append(names, name)
+		}
+	}
+	if len(names) > 0 {
+		sort.Strings(names)
+		for _, name := range names {
+			delete(aliases, name)
+			cleaned = append(cleaned, "alias "+name)
+		}
+		if err := gb.writeAliases(aliases); err != nil {
+			return cleaned, err
+		}
+	}
+
+	dv, err := gb.DefaultVersion()
+	if err != nil {
+		return cleaned, err
+	}
+	if dv == version {
+		if err := os.Remove(filepath.Join(gb.installDir, defaultFile)); err != nil {
+			return cleaned, err
+		}
+		cleaned = append(cleaned, defaultAlias)
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,95 @@
+package gobrew
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestUninstallCleansReferences(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	installSizedVersion(t, gb, "1.16", 1)
+	installSizedVersion(t, gb, "1.17", 1)
+	for name, version := range map[string]string{"old": "1.16", "legacy": "1.16", "stable": "1.17"} {
+		if err := gb.Alias(name, version); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := gb.SetDefault("1.16"); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	gb.stdout = &buf
+
+	gb.Uninstall("1.16")
+
+	aliases, err := gb.Aliases()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(aliases) != 1 || aliases["stable"] != "1.17" {
+		t.Errorf("expected only the stable alias left, got %v", aliases)
+	}
+	if dv, _ := gb.DefaultVersion(); dv != "" {
+		t.Errorf("expected the default cleared, got %q", dv)
+	}
+	for _, ref := range []string{"alias legacy", "alias old", "default"} {
+		if !strings.Contains(buf.String(), "Removed "+ref+" pointing at 1.16") {
+			t.Errorf("expected %q reported in %q", ref, buf.String())
+		}
+	}
+}
+
+func TestUseResolvesAlias(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	installSizedVersion(t, gb, "1.17", 1)
+	os.MkdirAll(gb.currentDir, os.ModePerm)
+	if err := gb.Alias("stable", "1.17"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gb.Alias("missing", "1.99"); err == nil {
+		t.Error("aliasing a version that isn't installed should fail")
+	}
+
+	gb.Use("stable")
+
+	if cv := gb.CurrentVersion(); cv != "1.17" {
+		t.Errorf("expected current version 1.17, got %q", cv)
+	}
+}
+
+// TestUseAliasAutoInstall goes through Use the way the use command does,
+// with auto install on
+func TestUseAliasAutoInstall(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	installSizedVersion(t, gb, "1.16", 1)
+	installSizedVersion(t, gb, "1.17", 1)
+	os.MkdirAll(gb.currentDir, os.ModePerm)
+	if err := gb.Alias("stable", "1.17"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gb.SetDefault("1.16"); err != nil {
+		t.Fatal(err)
+	}
+	downloads := 0
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downloads++
+		http.NotFound(w, r)
+	}))
+	defer registry.Close()
+	gb.registryPath = registry.URL + "/"
+	gb.EnableAutoInstall()
+
+	for name, want := range map[string]string{"stable": "1.17", "default": "1.16", "system": "system"} {
+		gb.Use(name)
+		if cv := gb.CurrentVersion(); cv != want {
+			t.Errorf("use %s: expected current version %s, got %q", name, want, cv)
+		}
+	}
+	if downloads != 0 {
+		t.Errorf("expected names resolving to installed versions not to be installed, got %d downloads", downloads)
+	}
+}
@@ -0,0 +1,122 @@
+package gobrew
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// knownPlatforms publish official archives, named <goos>-<arch>. Others may
+// still work for newer versions, they are only warned about
+var knownPlatforms = map[string]bool{
+	"aix-ppc64":       true,
+	"darwin-amd64":    true,
+	"darwin-arm64":    true,
+	"dragonfly-amd64": true,
+	"freebsd-386":     true,
+	"freebsd-amd64":   true,
+	"freebsd-arm64":   true,
+	"freebsd-armv6l":  true,
+	"freebsd-riscv64": true,
+	"illumos-amd64":   true,
+	"linux-386":       true,
+	"linux-amd64":     true,
+	"linux-arm64":     true,
+	"linux-armv6l":    true,
+	"linux-loong64":   true,
+	"linux-mips":      true,
+	"linux-mips64":    true,
+	"linux-mips64le":  true,
+	"linux-mipsle":    true,
+	"linux-ppc64":     true,
+	"linux-ppc64le":   true,
+	"linux-riscv64":   true,
+	"linux-s390x":     true,
+	"netbsd-386":      true,
+	"netbsd-amd64":    true,
+	"netbsd-arm64":    true,
+	"netbsd-armv6l":   true,
+	"openbsd-386":     true,
+	"openbsd-amd64":   true,
+	"openbsd-arm64":   true,
+	"openbsd-armv6l":  true,
+	"openbsd-ppc64":   true,
+	"openbsd-riscv64": true,
+	"plan9-386":       true,
+	"plan9-amd64":     true,
+	"plan9-arm":       true,
+	"solaris-amd64":   true,
+	"windows-386":     true,
+	"windows-amd64":   true,
+	"windows-arm":     true,
+	"windows-arm64":   true,
+}
+
+// archiveArch is the arch goarch archives are published under, 32-bit arm
+// archives are built for armv6l except on plan9 and windows
+func archiveArch(goos string, goarch string) string {
+	if goarch == "arm" && goos != "plan9" && goos != "windows" {
+		return "armv6l"
+	}
+	return goarch
+}
+
+func platform(goos string, goarch string) string {
+	return goos + "-" + archiveArch(goos, goarch)
+}
+
+// checkBuild reports whether version publishes an archive for goos/goarch
+func checkBuild(releases []Release, version string, goos string, goarch string) error {
+	release, ok := findRelease(releases, version)
+	if !ok {
+		return fmt.Errorf("version %s has not been released", version)
+	}
+	if _, ok := hostArchive(release, goos, archiveArch(goos, goarch)); !ok {
+		published := publishedPlatforms(release)
+		if len(published) == 0 {
+			return fmt.Errorf("version %s has no build for %s, it publishes no archives", version, platform(goos, goarch))
+		}
+		return fmt.Errorf("version %s has no build for %s, it has builds for: %s", version, platform(goos, goarch), strings.Join(published, ", "))
+	}
+	return nil
+}
+
+// publishedPlatforms lists the os-arch of every archive of release, sorted
+func publishedPlatforms(release Release) []string {
+	platforms := make([]string, 0, len(release.Files))
+	for _, file := range release.Files {
+		if file.Kind == "archive" {
+			platforms = append(platforms, file.OS+"-"+file.Arch)
+		}
+	}
+	sort.Strings(platforms)
+	return platforms
+}
+
+// checkHostBuild fails when the JSON API lists version without an archive for
+// this platform, so Install can stop before downloading. Versions it doesn't
+// list and an unreachable API are left to the download
+func (gb *GoBrew) checkHostBuild(version string) error {
+	releases, err := gb.fetchReleases()
+	if err != nil {
+		gb.debug.Printf("not checking the builds of %s: %s", version, err)
+		return nil
+	}
+	if _, ok := findRelease(releases, version); !ok {
+		gb.debug.Printf("not checking the builds of %s: not listed by the JSON API", version)
+		return nil
+	}
+	return checkBuild(releases, version, runtime.GOOS, runtime.GOARCH)
+}
+
+// explainMissingBuild checks with the JSON API why version could not be
+// downloaded, nil when it can't tell
+func (gb *GoBrew) explainMissingBuild(version string) error {
+	releases, err := gb.fetchReleases()
+	if err != nil {
+		gb.debug.Printf("checking the builds of %s: %s", version, err)
+		return nil
+	}
+	return checkBuild(releases, version, runtime.GOOS, runtime.GOARCH)
+}
@@ -0,0 +1,117 @@
+package gobrew
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+const loongReleases = `[
+  {"version": "go1.21.0", "stable": true, "files": [
+    {"filename": "go1.21.0.linux-amd64.tar.gz", "os": "linux", "arch": "amd64", "version": "go1.21.0", "sha256": "eee1", "size": 66000000, "kind": "archive"},
+    {"filename": "go1.21.0.linux-loong64.tar.gz", "os": "linux", "arch": "loong64", "version": "go1.21.0", "sha256": "eee2", "size": 64000000, "kind": "archive"},
+    {"filename": "go1.21.0.linux-armv6l.tar.gz", "os": "linux", "arch": "armv6l", "version": "go1.21.0", "sha256": "eee3", "size": 63000000, "kind": "archive"}
+  ]},
+  {"version": "go1.16.13", "stable": true, "files": [
+    {"filename": "go1.16.13.linux-amd64.tar.gz", "os": "linux", "arch": "amd64", "version": "go1.16.13", "sha256": "ccc1", "size": 129000000, "kind": "archive"}
+  ]}
+]`
+
+func TestPlatform(t *testing.T) {
+	for _, tc := range []struct{ goos, goarch, want string }{
+		{"linux", "amd64", "linux-amd64"},
+		{"linux", "loong64", "linux-loong64"},
+		{"linux", "arm", "linux-armv6l"},
+		{"windows", "arm", "windows-arm"},
+	} {
+		got := platform(tc.goos, tc.goarch)
+		if got != tc.want {
+			t.Errorf("%s/%s: expected %s, got %s", tc.goos, tc.goarch, tc.want, got)
+		}
+		if !knownPlatforms[got] {
+			t.Errorf("expected %s to be a known platform", got)
+		}
+	}
+	if knownPlatforms["linux-arm"] {
+		t.Error("linux-arm has no archives, armv6l does")
+	}
+}
+
+func TestCheckBuild(t *testing.T) {
+	releases, err := decodeReleases(strings.NewReader(loongReleases))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := checkBuild(releases, "1.21.0", "linux", "loong64"); err != nil {
+		t.Errorf("expected a loong64 build of 1.21.0, got %v", err)
+	}
+	if err := checkBuild(releases, "1.21.0", "linux", "arm"); err != nil {
+		t.Errorf("expected the armv6l build for linux/arm, got %v", err)
+	}
+	err = checkBuild(releases, "1.16.13", "linux", "loong64")
+	if err == nil || err.Error() != "version 1.16.13 has no build for linux-loong64, it has builds for: linux-amd64" {
+		t.Errorf("expected no loong64 build of 1.16.13, got %v", err)
+	}
+	if err := checkBuild(releases, "1.99", "linux", "amd64"); err == nil || !strings.Contains(err.Error(), "not been released") {
+		t.Errorf("expected an unreleased error, got %v", err)
+	}
+}
+
+const oldReleases = `[
+  {"version": "go1.4.3", "stable": false, "files": [
+    {"filename": "go1.4.3.src.tar.gz", "os": "", "arch": "", "version": "go1.4.3", "sha256": "fff0", "size": 10000000, "kind": "source"},
+    {"filename": "go1.4.3.linux-amd64.tar.gz", "os": "linux", "arch": "amd64", "version": "go1.4.3", "sha256": "fff1", "size": 62000000, "kind": "archive"},
+    {"filename": "go1.4.3.darwin-amd64.tar.gz", "os": "darwin", "arch": "amd64", "version": "go1.4.3", "sha256": "fff2", "size": 61000000, "kind": "archive"},
+    {"filename": "go1.4.3.linux-386.tar.gz", "os": "linux", "arch": "386", "version": "go1.4.3", "sha256": "fff3", "size": 55000000, "kind": "archive"}
+  ]}
+]`
+
+func TestCheckBuildSuggestsPlatforms(t *testing.T) {
+	releases, err := decodeReleases(strings.NewReader(oldReleases))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = checkBuild(releases, "1.4.3", "linux", "arm64")
+	want := "version 1.4.3 has no build for linux-arm64, it has builds for: darwin-amd64, linux-386, linux-amd64"
+	if err == nil || err.Error() != want {
+		t.Errorf("got %v, want %s", err, want)
+	}
+}
+
+func TestInstallMissingHostBuild(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	other := "plan9-386"
+	if gb.getArch() == other {
+		other = "linux-amd64"
+	}
+	parts := strings.SplitN(other, "-", 2)
+	serveReleases(t, &gb, `[{"version": "go1.4.3", "stable": false, "files": [
+		{"filename": "go1.4.3.`+other+`.tar.gz", "os": "`+parts[0]+`", "arch": "`+parts[1]+`", "version": "go1.4.3", "kind": "archive"}
+	]}]`)
+	downloads := 0
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { downloads++ }))
+	defer registry.Close()
+	gb.registryPath = registry.URL + "/"
+	var stderr bytes.Buffer
+	gb.stderr = &stderr
+	var exitCode int
+	osExit = func(code int) { exitCode = code }
+	defer func() { osExit = os.Exit }()
+
+	gb.Install("1.4.3")
+
+	if exitCode != 1 || downloads != 0 {
+		t.Errorf("expected the install stopped before downloading, exit %d after %d downloads", exitCode, downloads)
+	}
+	if !strings.Contains(stderr.String(), "it has builds for: "+other) {
+		t.Errorf("expected the published builds listed, got %q", stderr.String())
+	}
+	if _, err := os.Stat(gb.getVersionDir("1.4.3")); !os.IsNotExist(err) {
+		t.Errorf("expected no version dir left behind, got %v", err)
+	}
+}
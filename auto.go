@@ -0,0 +1,184 @@
+package gobrew
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kevincobain2000/gobrew/utils"
+)
+
+// toolVersionsFile is where asdf pins the versions of a project's tools
+const toolVersionsFile = ".tool-versions"
+
+// versionSource resolves the go version a directory asks for, returning an
+// empty version when it doesn't say
+type versionSource struct {
+	name    string
+	resolve func(dir string) (string, error)
+}
+
+// versionSources in order of precedence
+var versionSources = []versionSource{
+	{name: "GOBREW_GO_VERSION", resolve: func(string) (string, error) { return strings.TrimSpace(os.Getenv("GOBREW_GO_VERSION")), nil }},
+	{name: goVersionFile, resolve: VersionFromGoVersionFile},
+	{name: toolVersionsFile, resolve: VersionFromToolVersions},
+	{name: "go.work", resolve: VersionFromGoWork},
+	{name: "go.mod", resolve: VersionFromGoMod},
+}
+
+// ResolveVersion returns the version dir asks for and where it came from:
+// the GOBREW_GO_VERSION env, then .go-version, then .tool-versions, then
+// go.work, then go.mod
+func ResolveVersion(dir string) (version string, source string, err error) {
+	for _, s := range versionSources {
+		version, err := s.resolve(dir)
+		if err != nil {
+			return "", s.name, err
+		}
+		if version != "" {
+			return version, s.name, nil
+		}
+	}
+	return "", "", fmt.Errorf("no go version found: set GOBREW_GO_VERSION or add %s, %s, go.work or go.mod to %s", goVersionFile, toolVersionsFile, dir)
+}
+
+// UseAuto installs and uses the version the working directory asks for
+func (gb *GoBrew) UseAuto() error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	version, source, err := ResolveVersion(dir)
+	if err != nil {
+		return err
+	}
+	utils.ColorInfo.Fprintf(gb.stdout, "[Info] Using version: %s from %s\n", version, source)
+	gb.Install(version)
+	gb.Use(version)
+	return nil
+}
+
+// SatisfyToolchain installs and uses the toolchain the go.mod in dir requires
+// with its toolchain directive, e.g. toolchain go1.21.3
+func (gb *GoBrew) SatisfyToolchain(dir string) error {
+	directives, err := readDirectives(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return err
+	}
+	toolchain := directives["toolchain"]
+	if toolchain == "" {
+		return fmt.Errorf("no toolchain directive in %s", filepath.Join(dir, "go.mod"))
+	}
+	version, err := normalizeVersion(toolchain)
+	if err != nil {
+		return fmt.Errorf("go.mod toolchain: %w", err)
+	}
+	utils.ColorInfo.Fprintf(gb.stdout, "[Info] go.mod requires toolchain: %s\n", version)
+	if !gb.existsVersion(version) {
+		gb.Install(version)
+	}
+	gb.Use(version)
+	return nil
+}
+
+// VersionFromGoVersionFile reads the version pinned in dir/.go-version
+func VersionFromGoVersionFile(dir string) (string, error) {
+	content, err := ioutil.ReadFile(filepath.Join(dir, goVersionFile))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(strings.TrimSpace(utils.BytesToString(content)), "go"), nil
+}
+
+// VersionFromToolVersions reads the golang (or go) line of an asdf
+// .tool-versions in dir, taking the first version when it lists several
+func VersionFromToolVersions(dir string) (string, error) {
+	f, err := os.Open(filepath.Join(dir, toolVersionsFile))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 || (fields[0] != "golang" && fields[0] != "go") {
+			continue
+		}
+		return strings.TrimPrefix(fields[1], "go"), nil
+	}
+	return "", scanner.Err()
+}
+
+// VersionFromGoMod reads dir/go.mod, preferring its toolchain directive over
+// its go directive
+func VersionFromGoMod(dir string) (string, error) {
+	directives, err := readDirectives(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return "", err
+	}
+	return directiveVersion(directives), nil
+}
+
+// VersionFromGoWork reads dir/go.work, preferring its toolchain directive
+// over its go directive
+func VersionFromGoWork(dir string) (string, error) {
+	directives, err := readDirectives(filepath.Join(dir, "go.work"))
+	if err != nil {
+		return "", err
+	}
+	return directiveVersion(directives), nil
+}
+
+// directiveVersion is the toolchain directive without its go prefix, or the go
+// directive when there is no toolchain
+func directiveVersion(directives map[string]string) string {
+	if toolchain := directives["toolchain"]; toolchain != "" {
+		return strings.TrimPrefix(toolchain, "go")
+	}
+	return directives["go"]
+}
+
+// readDirectives returns the first value of each single line directive in a
+// go.mod style file, or nothing when the file doesn't exist
+func readDirectives(path string) (map[string]string, error) {
+	directives := make(map[string]string)
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return directives, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		if _, ok := directives[fields[0]]; !ok {
+			directives[fields[0]] = fields[1]
+		}
+	}
+	return directives, scanner.Err()
+}
@@ -0,0 +1,150 @@
+package gobrew
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveVersionPrecedence(t *testing.T) {
+	dir := tempDir(t)
+	ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/m\n\ngo 1.17\n"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, goVersionFile), []byte("1.16.3\n"), 0644)
+	os.Setenv("GOBREW_GO_VERSION", "1.18")
+	defer os.Unsetenv("GOBREW_GO_VERSION")
+
+	steps := []struct {
+		name        string
+		prepare     func()
+		wantVersion string
+		wantSource  string
+	}{
+		{name: "env wins", prepare: func() {}, wantVersion: "1.18", wantSource: "GOBREW_GO_VERSION"},
+		{name: ".go-version over go.mod", prepare: func() { os.Unsetenv("GOBREW_GO_VERSION") }, wantVersion: "1.16.3", wantSource: goVersionFile},
+		{name: "go.mod last", prepare: func() { os.Remove(filepath.Join(dir, goVersionFile)) }, wantVersion: "1.17", wantSource: "go.mod"},
+	}
+	for _, step := range steps {
+		step.prepare()
+		version, source, err := ResolveVersion(dir)
+		if err != nil {
+			t.Fatalf("%s: %s", step.name, err)
+		}
+		if version != step.wantVersion || source != step.wantSource {
+			t.Errorf("%s: got (%s, %s), want (%s, %s)", step.name, version, source, step.wantVersion, step.wantSource)
+		}
+	}
+
+	os.Remove(filepath.Join(dir, "go.mod"))
+	if _, _, err := ResolveVersion(dir); err == nil {
+		t.Error("expected an error when no source names a version")
+	}
+}
+
+func TestVersionFromGoWork(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "go directive", content: "go 1.21\n\nuse ./m\n", want: "1.21"},
+		{name: "toolchain directive", content: "go 1.21\n\ntoolchain go1.21.3\n\nuse (\n\t./a\n\t./b\n)\n", want: "1.21.3"},
+	}
+	for _, tt := range tests {
+		dir := tempDir(t)
+		ioutil.WriteFile(filepath.Join(dir, "go.work"), []byte(tt.content), 0644)
+		got, err := VersionFromGoWork(dir)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	if got, err := VersionFromGoWork(tempDir(t)); err != nil || got != "" {
+		t.Errorf("missing go.work: got (%q, %v), want empty", got, err)
+	}
+}
+
+func TestVersionFromToolVersions(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "golang", content: "nodejs 20.10.0\ngolang 1.21.5\npython 3.12.0\n", want: "1.21.5"},
+		{name: "go", content: "go 1.20.3 # pinned\n", want: "1.20.3"},
+		{name: "several versions", content: "golang 1.21.5 1.20.12\n", want: "1.21.5"},
+		{name: "commented out", content: "# golang 1.19\nnodejs 20.10.0\n", want: ""},
+	}
+	for _, tt := range tests {
+		dir := tempDir(t)
+		ioutil.WriteFile(filepath.Join(dir, toolVersionsFile), []byte(tt.content), 0644)
+		got, err := VersionFromToolVersions(dir)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	dir := tempDir(t)
+	ioutil.WriteFile(filepath.Join(dir, toolVersionsFile), []byte("golang 1.21.5\n"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/m\n\ngo 1.17\n"), 0644)
+	if version, source, err := ResolveVersion(dir); err != nil || version != "1.21.5" || source != toolVersionsFile {
+		t.Errorf("got (%s, %s, %v), want (1.21.5, %s)", version, source, err, toolVersionsFile)
+	}
+}
+
+func TestResolveVersionGoWorkOverGoMod(t *testing.T) {
+	dir := tempDir(t)
+	ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/m\n\ngo 1.17\n"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "go.work"), []byte("go 1.21\n"), 0644)
+
+	version, source, err := ResolveVersion(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != "1.21" || source != "go.work" {
+		t.Errorf("got (%s, %s), want (1.21, go.work)", version, source)
+	}
+}
+
+func TestVersionFromGoModToolchain(t *testing.T) {
+	dir := tempDir(t)
+	ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/m\n\ngo 1.21\n\ntoolchain go1.22.1\n"), 0644)
+
+	got, err := VersionFromGoMod(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "1.22.1" {
+		t.Errorf("got %q, want the toolchain 1.22.1", got)
+	}
+}
+
+func TestSatisfyToolchain(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	gb.registryPath = serveArchives(t, gb, "1.21.3").URL + "/"
+	installFakeVersion(t, gb, "1.21.0", "go version go1.21.0 linux/amd64")
+	useFixture(t, gb, "1.21.0")
+	dir := tempDir(t)
+	ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/m\n\ngo 1.21\n\ntoolchain go1.21.3\n"), 0644)
+
+	if err := gb.SatisfyToolchain(dir); err != nil {
+		t.Fatal(err)
+	}
+	if !gb.existsVersion("1.21.3") {
+		t.Error("expected the required toolchain installed")
+	}
+	if cv := gb.CurrentVersion(); cv != "1.21.3" {
+		t.Errorf("expected 1.21.3 current, got %q", cv)
+	}
+
+	ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/m\n\ngo 1.21\n"), 0644)
+	if err := gb.SatisfyToolchain(dir); err == nil {
+		t.Error("expected an error without a toolchain directive")
+	}
+}
@@ -1,17 +1,20 @@
 #! /bin/sh
 
+PKG=github.com/kevincobain2000/gobrew
+LDFLAGS="-X $PKG.buildVersion=$(git describe --tags --always) -X $PKG.buildCommit=$(git rev-parse --short HEAD) -X $PKG.buildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+
 echo "building linux 64"
-GOOS=linux GOARCH=amd64 go build cmd/gobrew/main.go && mv main bin/gobrew-linux-64
+GOOS=linux GOARCH=amd64 go build -ldflags "$LDFLAGS" cmd/gobrew/main.go && mv main bin/gobrew-linux-64
 echo "building linux done"
 
 echo "building darwin 64"
-GOOS=darwin GOARCH=amd64 go build cmd/gobrew/main.go && mv main bin/gobrew-darwin-64
+GOOS=darwin GOARCH=amd64 go build -ldflags "$LDFLAGS" cmd/gobrew/main.go && mv main bin/gobrew-darwin-64
 echo "building darwin done"
 
 echo "building darwin arm-64 (m1)"
-GOOS=darwin GOARCH=arm64 go build cmd/gobrew/main.go && mv main bin/gobrew-darwin-arm-64
+GOOS=darwin GOARCH=arm64 go build -ldflags "$LDFLAGS" cmd/gobrew/main.go && mv main bin/gobrew-darwin-arm-64
 echo "building darwin arm-64 (m1) done"
 
 echo "building windows 64"
-GOOS=windows GOARCH=amd64 go build cmd/gobrew/main.go && mv main.exe bin/gobrew-windows-64.exe
+GOOS=windows GOARCH=amd64 go build -ldflags "$LDFLAGS" cmd/gobrew/main.go && mv main.exe bin/gobrew-windows-64.exe
 echo "building windows done"
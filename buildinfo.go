@@ -0,0 +1,46 @@
+package gobrew
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// Set at build time, see build.sh, e.g.
+// -ldflags "-X github.com/kevincobain2000/gobrew.buildVersion=v1.8.0"
+var (
+	buildVersion = "dev"
+	buildCommit  = ""
+	buildDate    = ""
+)
+
+// BuildInfo describes the gobrew binary itself, not a managed go version
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	Date      string `json:"date,omitempty"`
+	GoVersion string `json:"go_version"`
+	Platform  string `json:"platform"`
+}
+
+// Version of gobrew, with the go it was built with and the platform it runs on
+func Version() BuildInfo {
+	return BuildInfo{
+		Version:   buildVersion,
+		Commit:    buildCommit,
+		Date:      buildDate,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+}
+
+func (b BuildInfo) String() string {
+	s := "gobrew " + b.Version
+	if b.Commit != "" {
+		s += " (" + b.Commit
+		if b.Date != "" {
+			s += ", built " + b.Date
+		}
+		s += ")"
+	}
+	return fmt.Sprintf("%s %s %s", s, b.GoVersion, b.Platform)
+}
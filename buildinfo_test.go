@@ -0,0 +1,28 @@
+package gobrew
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestVersion(t *testing.T) {
+	defer func(version, commit, date string) {
+		buildVersion, buildCommit, buildDate = version, commit, date
+	}(buildVersion, buildCommit, buildDate)
+	buildVersion, buildCommit, buildDate = "v1.8.0", "abc1234", "2023-09-01T10:00:00Z"
+
+	info := Version()
+	if info.Version != "v1.8.0" || info.Commit != "abc1234" || info.Date != "2023-09-01T10:00:00Z" {
+		t.Errorf("expected the build variables, got %+v", info)
+	}
+	if info.GoVersion != runtime.Version() {
+		t.Errorf("expected go version %s, got %s", runtime.Version(), info.GoVersion)
+	}
+	if info.Platform != runtime.GOOS+"/"+runtime.GOARCH {
+		t.Errorf("expected the host platform, got %s", info.Platform)
+	}
+	if s := info.String(); !strings.HasPrefix(s, "gobrew v1.8.0 (abc1234, built 2023-09-01T10:00:00Z) go") {
+		t.Errorf("unexpected string %q", s)
+	}
+}
@@ -0,0 +1,55 @@
+package gobrew
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	releasesCacheFile       = "releases.json"
+	defaultReleasesCacheTTL = time.Hour
+)
+
+// releasesCache is the last JSON API response, with what is needed to
+// revalidate it
+type releasesCache struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	FetchedAt    time.Time `json:"fetched_at"`
+	Releases     []Release `json:"releases"`
+}
+
+// readReleasesCache returns nil when there is no usable cache for releasesURL
+func (gb *GoBrew) readReleasesCache() *releasesCache {
+	content, err := ioutil.ReadFile(filepath.Join(gb.installDir, releasesCacheFile))
+	if err != nil {
+		return nil
+	}
+	var cache releasesCache
+	if err := json.Unmarshal(content, &cache); err != nil {
+		gb.debug.Printf("ignoring corrupt %s: %s", releasesCacheFile, err)
+		return nil
+	}
+	if cache.URL != gb.releasesURL {
+		return nil
+	}
+	return &cache
+}
+
+// writeReleasesCache is best effort, failing only means fetching again
+func (gb *GoBrew) writeReleasesCache(cache *releasesCache) {
+	content, err := json.Marshal(cache)
+	if err == nil {
+		err = os.MkdirAll(gb.installDir, os.ModePerm)
+	}
+	if err == nil {
+		err = ioutil.WriteFile(filepath.Join(gb.installDir, releasesCacheFile), content, 0644)
+	}
+	if err != nil {
+		gb.debug.Printf("caching releases: %s", err)
+	}
+}
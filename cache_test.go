@@ -0,0 +1,69 @@
+package gobrew
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestReleasesCacheRevalidates(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	var requests []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Header)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Wed, 12 Jan 2022 18:00:00 GMT")
+		w.Write([]byte(releasesFixture))
+	}))
+	defer server.Close()
+	gb.releasesURL = server.URL + "/?mode=json"
+
+	first, err := gb.fetchReleases()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// within the ttl the cache is used as is
+	if _, err := gb.fetchReleases(); err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 1 {
+		t.Fatalf("expected the cache used within its ttl, got %d requests", len(requests))
+	}
+
+	gb.releasesCacheTTL = 0
+	second, err := gb.fetchReleases()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("expected the cache revalidated, got %d requests", len(requests))
+	}
+	if got := requests[1].Get("If-Modified-Since"); got != "Wed, 12 Jan 2022 18:00:00 GMT" {
+		t.Errorf("expected If-Modified-Since sent, got %q", got)
+	}
+	if len(second) != 4 || !reflect.DeepEqual(first, second) {
+		t.Errorf("expected the cached releases on a 304, got %+v", second)
+	}
+}
+
+func TestReleasesCachePerURL(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	serveReleases(t, &gb, releasesFixture)
+	if _, err := gb.fetchReleases(); err != nil {
+		t.Fatal(err)
+	}
+
+	serveReleases(t, &gb, `[{"version": "go1.18", "stable": true, "files": []}]`)
+	releases, err := gb.fetchReleases()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(releases) != 1 || releases[0].Version != "go1.18" {
+		t.Errorf("expected the cache of another url ignored, got %+v", releases)
+	}
+}
@@ -0,0 +1,147 @@
+package gobrew
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrChecksumMismatch is returned when an archive doesn't hash to the expected sha256
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// fileSHA256 returns the hex encoded sha256 of the file at path
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// verifyChecksum checks the file at path hashes to the hex encoded sha256 expected
+func verifyChecksum(path string, expected string) error {
+	actual, err := fileSHA256(path)
+	if err != nil {
+		return err
+	}
+	return matchChecksum(path, actual, expected)
+}
+
+func matchChecksum(path string, actual string, expected string) error {
+	if !strings.EqualFold(actual, strings.TrimSpace(expected)) {
+		return fmt.Errorf("%w: %s is %s, expected %s", ErrChecksumMismatch, path, actual, expected)
+	}
+	return nil
+}
+
+// publishedChecksum looks up the sha256 the JSON API publishes for the
+// release file named filename
+func (gb *GoBrew) publishedChecksum(filename string) (string, error) {
+	file, err := gb.publishedFile(filename)
+	if err != nil {
+		return "", fmt.Errorf("no published checksum for %s: %w", filename, err)
+	}
+	return file.SHA256, nil
+}
+
+// publishedFile looks up the release file named filename in the JSON API
+func (gb *GoBrew) publishedFile(filename string) (Build, error) {
+	releases, err := gb.fetchReleases()
+	if err != nil {
+		return Build{}, err
+	}
+	for _, release := range releases {
+		for _, file := range release.Files {
+			if file.Filename == filename {
+				return file, nil
+			}
+		}
+	}
+	return Build{}, fmt.Errorf("%s is not published", filename)
+}
+
+// cachedArchiveValid reports whether archive was already downloaded and
+// matches its published checksum, so it can be extracted without a new download
+func (gb *GoBrew) cachedArchiveValid(archive string) bool {
+	if _, err := os.Stat(archive); err != nil {
+		return false
+	}
+	expected, err := gb.publishedChecksum(filepath.Base(archive))
+	if err != nil {
+		gb.debug.Printf("not reusing %s: %s", archive, err)
+		return false
+	}
+	actual, err := gb.cachedSHA256(archive)
+	if err == nil {
+		err = matchChecksum(archive, actual, expected)
+	}
+	if err != nil {
+		gb.debug.Printf("not reusing %s: %s", archive, err)
+		return false
+	}
+	return true
+}
+
+const checksumsFile = "checksums.json"
+
+// hashFile is swapped out by tests
+var hashFile = fileSHA256
+
+// checksumEntry is the sha256 of a file as long as its size and mtime are unchanged
+type checksumEntry struct {
+	Size    int64  `json:"size"`
+	ModTime int64  `json:"mtime"`
+	SHA256  string `json:"sha256"`
+}
+
+// cachedSHA256 returns the sha256 of path, only hashing it again when it
+// changed since the last time. Checksums are kept in checksums.json by path
+func (gb *GoBrew) cachedSHA256(path string) (string, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	cacheFile := filepath.Join(gb.installDir, checksumsFile)
+	checksums := make(map[string]checksumEntry)
+	if content, err := ioutil.ReadFile(cacheFile); err == nil {
+		if err := json.Unmarshal(content, &checksums); err != nil {
+			gb.debug.Printf("ignoring corrupt %s: %s", checksumsFile, err)
+			checksums = make(map[string]checksumEntry)
+		}
+	}
+	entry, ok := checksums[path]
+	if ok && entry.Size == fi.Size() && entry.ModTime == fi.ModTime().UnixNano() {
+		return entry.SHA256, nil
+	}
+
+	sum, err := hashFile(path)
+	if err != nil {
+		return "", err
+	}
+	checksums[path] = checksumEntry{Size: fi.Size(), ModTime: fi.ModTime().UnixNano(), SHA256: sum}
+	// forget files that are gone
+	for cached := range checksums {
+		if _, err := os.Stat(cached); err != nil {
+			delete(checksums, cached)
+		}
+	}
+	if content, err := json.Marshal(checksums); err == nil {
+		if err := ioutil.WriteFile(cacheFile, content, 0644); err != nil {
+			gb.debug.Printf("caching checksums: %s", err)
+		}
+	}
+	return sum, nil
+}
@@ -0,0 +1,96 @@
+package gobrew
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingRegistry serves nothing but counts the archive requests it gets
+func countingRegistry(t *testing.T, gb *GoBrew) *int32 {
+	t.Helper()
+	var fetches int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(server.Close)
+	gb.registryPath = server.URL + "/"
+	return &fetches
+}
+
+func TestDownloadAndExtractReusesCachedArchive(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	fetches := countingRegistry(t, &gb)
+
+	tarName := "go1.16." + gb.getArch() + ".tar.gz"
+	archive := filepath.Join(gb.downloadsDir, tarName)
+	writeTarGz(t, archive, map[string]string{"go/bin/go": "go"})
+	sum, _ := fileSHA256(archive)
+	serveReleases(t, &gb, fmt.Sprintf(`[{"version": "go1.16", "stable": true, "files": [{"filename": %q, "sha256": %q}]}]`, tarName, sum))
+
+	if err := gb.mkdirs("1.16"); err != nil {
+		t.Fatal(err)
+	}
+	gb.downloadAndExtract("1.16")
+
+	if n := atomic.LoadInt32(fetches); n != 0 {
+		t.Errorf("expected no archive download, got %d", n)
+	}
+	if !gb.existsVersion("1.16") {
+		t.Error("expected 1.16 extracted from the cached archive")
+	}
+}
+
+func TestCachedArchiveInvalidChecksum(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	tarName := "go1.16." + gb.getArch() + ".tar.gz"
+	archive := filepath.Join(gb.downloadsDir, tarName)
+	writeTarGz(t, archive, map[string]string{"go/bin/go": "go"})
+	serveReleases(t, &gb, fmt.Sprintf(`[{"version": "go1.16", "stable": true, "files": [{"filename": %q, "sha256": "0000"}]}]`, tarName))
+
+	if gb.cachedArchiveValid(archive) {
+		t.Error("an archive not matching its published checksum must be downloaded again")
+	}
+}
+
+func TestCachedSHA256(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	archive := filepath.Join(tempDir(t), "go1.16.tar.gz")
+	writeTarGz(t, archive, map[string]string{"go/bin/go": "go"})
+	want, _ := fileSHA256(archive)
+
+	var hashes int
+	hashFile = func(path string) (string, error) {
+		hashes++
+		return fileSHA256(path)
+	}
+	defer func() { hashFile = fileSHA256 }()
+
+	for i := 0; i < 2; i++ {
+		sum, err := gb.cachedSHA256(archive)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sum != want {
+			t.Errorf("expected %s, got %s", want, sum)
+		}
+	}
+	if hashes != 1 {
+		t.Errorf("expected the archive hashed once, got %d", hashes)
+	}
+
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(archive, later, later)
+	if _, err := gb.cachedSHA256(archive); err != nil {
+		t.Fatal(err)
+	}
+	if hashes != 2 {
+		t.Errorf("expected the archive hashed again after its mtime changed, got %d", hashes)
+	}
+}
@@ -5,15 +5,55 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/kevincobain2000/gobrew"
+	"github.com/kevincobain2000/gobrew/utils"
 )
 
 var args = []string{}
 var actionArg = ""
 var versionArg = ""
 
-var allowedArgs = []string{"h", "help", "ls", "list", "ls-remote", "install", "use", "uninstall", "self-update"}
+var installFlags = flag.NewFlagSet("install", flag.ExitOnError)
+var fromArg = installFlags.String("from", "", "install from this archive url or file instead of the registry")
+var checksumArg = installFlags.String("checksum", "", "expected sha256 of the --from archive")
+var verifyExistingArg = installFlags.Bool("verify-existing", false, "verify an already installed version and reinstall it when corrupt")
+var keepDownloadsArg = installFlags.Bool("keep-downloads", false, "keep the downloaded archive for reinstalls, like GOBREW_KEEP_DOWNLOADS=1")
+
+var pruneFlags = flag.NewFlagSet("prune", flag.ExitOnError)
+var keepArg = pruneFlags.Int("keep", 0, "keep this many of the newest versions besides the current one")
+var dryRunArg = pruneFlags.Bool("dry-run", false, "only report what would be removed")
+
+var upgradeAllFlags = flag.NewFlagSet("upgrade-all", flag.ExitOnError)
+var removeSupersededArg = upgradeAllFlags.Bool("remove-superseded", false, "uninstall the patch each minor line was upgraded from")
+
+var lsRemoteFlags = flag.NewFlagSet("ls-remote", flag.ExitOnError)
+var detailsArg = lsRemoteFlags.Bool("details", false, "show the size and sha256 of each archive for this platform")
+var hostArg = lsRemoteFlags.Bool("host", false, "only list versions with an archive for this platform")
+
+var useFlags = flag.NewFlagSet("use", flag.ExitOnError)
+var prefixArg = useFlags.String("prefix", "", "link the version as <root>/<prefix>/bin instead of the current one")
+var installArg = useFlags.Bool("install", true, "install the version first when it is missing, like GOBREW_AUTO_INSTALL=1")
+var temporaryArg = useFlags.Bool("temporary", false, "use the version only while running the command after --")
+
+var useExternalFlags = flag.NewFlagSet("use-external", flag.ExitOnError)
+var commitArg = useExternalFlags.String("commit", "", "register the GOROOT as the build of this commit, named commit-<hash>")
+
+var doctorFlags = flag.NewFlagSet("doctor", flag.ExitOnError)
+var jsonArg = doctorFlags.Bool("json", false, "print the diagnostics as JSON")
+var networkArg = doctorFlags.Bool("network", false, "also check the registry and tags repo are reachable")
+
+var listFlags = flag.NewFlagSet("list", flag.ExitOnError)
+var plainArg = listFlags.Bool("plain", false, "list version names only, one per line, for scripts")
+var groupedArg = listFlags.Bool("grouped", false, "list versions grouped by minor line")
+var tableArg = listFlags.Bool("table", false, "list versions as a table with size and installed date")
+var outdatedArg = listFlags.Bool("outdated", false, "list the installed minor lines with a newer patch released")
+var formatArg = listFlags.String("format", "", "text/template applied to each version, e.g. '{{.Version}} {{.Current}}'")
+
+var allowedArgs = []string{"h", "help", "ls", "list", "ls-remote", "install", "use", "use-previous", "use-auto", "use-toolchain", "use-external", "import", "uninstall", "verify", "doctor", "check-update", "upgrade-all", "pin", "protect", "unprotect", "prune", "reset", "builds", "resolve", "url", "notes", "seed-mirror", "exec", "shellenv", "which", "goroot", "vars", "hook", "ensure-path", "tool", "alias", "unalias", "default", "config", "version", "self-update"}
 
 func init() {
 	log.SetFlags(0)
@@ -32,30 +72,324 @@ func init() {
 	}
 
 	actionArg = args[0]
-	if len(args) == 2 {
+	if len(args) >= 2 {
 		versionArg = args[1]
 	}
+
+	switch actionArg {
+	case "ls", "list":
+		listFlags.Parse(args[1:])
+	case "ls-remote":
+		lsRemoteFlags.Parse(args[1:])
+	case "prune":
+		pruneFlags.Parse(args[1:])
+	case "doctor":
+		doctorFlags.Parse(args[1:])
+	case "upgrade-all":
+		upgradeAllFlags.Parse(args[1:])
+	case "install":
+		if len(args) > 2 {
+			installFlags.Parse(args[2:])
+		}
+	case "use":
+		if len(args) > 2 {
+			useFlags.Parse(args[2:])
+		}
+	case "use-external":
+		if len(args) > 2 {
+			useExternalFlags.Parse(args[2:])
+		}
+	}
 }
 
 func main() {
-	gb := gobrew.NewGoBrew()
+	gb, err := gobrew.NewGoBrew()
+	exitOnError(err)
+	gb.EnableSignalCleanup()
+	start := time.Now()
+	recordMetric = func() {
+		if err := gb.RecordMetric(actionArg, versionArg, time.Since(start)); err != nil {
+			utils.ColorError.Fprintf(os.Stderr, "[Error] Recording metrics: %s\n", err)
+		}
+	}
+	defer recordMetric()
 	switch actionArg {
 	case "h", "help":
 		log.Print(usage())
 	case "ls", "list":
-		gb.ListVersions()
+		if *plainArg {
+			exitOnError(gb.ListPlain())
+			break
+		}
+		if *groupedArg {
+			exitOnError(gb.ListGrouped())
+			break
+		}
+		if *tableArg {
+			exitOnError(gb.ListVersionsTable(os.Stdout))
+			break
+		}
+		if *outdatedArg {
+			outdated, err := gb.Outdated()
+			exitOnError(err)
+			for _, o := range outdated {
+				fmt.Printf("%s -> %s\n", o.Installed, o.Latest)
+			}
+			break
+		}
+		if *formatArg == "" {
+			gb.ListVersions()
+			break
+		}
+		tmpl, err := gobrew.ParseListFormat(*formatArg)
+		exitOnError(err)
+		exitOnError(gb.ListVersionsFormat(os.Stdout, tmpl))
 	case "ls-remote":
-		gb.ListRemoteVersions()
+		if *hostArg {
+			versions, err := gb.RemoteVersionsForHost()
+			exitOnError(err)
+			for _, v := range versions {
+				fmt.Println(v)
+			}
+			break
+		}
+		if !*detailsArg {
+			gb.ListRemoteVersions()
+			break
+		}
+		versions, err := gb.RemoteVersionsWithBuilds()
+		exitOnError(err)
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSION\tSIZE\tSHA256")
+		for _, v := range versions {
+			size := "-"
+			if v.Size > 0 {
+				size = utils.HumanSize(v.Size)
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", v.Version, size, v.SHA256)
+		}
+		tw.Flush()
 	case "install":
-		gb.Install(versionArg)
+		if *verifyExistingArg {
+			gb.EnableVerifyExisting()
+		}
+		if *keepDownloadsArg {
+			gb.EnableKeepDownloads()
+		}
+		if *fromArg != "" {
+			installFrom(&gb, versionArg, *fromArg, *checksumArg)
+		} else {
+			gb.Install(versionArg)
+		}
 		if gb.CurrentVersion() == "" {
 			gb.Use(versionArg)
 		}
 	case "use":
-		gb.Install(versionArg)
+		if *prefixArg != "" {
+			exitOnError(gb.UseNamed(*prefixArg, versionArg))
+			break
+		}
+		if *temporaryArg {
+			exitOnError(gb.ExecTemporary(versionArg, useFlags.Args()))
+			break
+		}
+		// Use resolves aliases, default, system and external names before
+		// installing what is missing
+		if *installArg {
+			gb.EnableAutoInstall()
+		}
 		gb.Use(versionArg)
+	case "use-previous":
+		exitOnError(gb.UsePrevious())
+	case "use-auto":
+		exitOnError(gb.UseAuto())
+	case "use-toolchain":
+		dir := versionArg
+		if dir == "" {
+			dir = "."
+		}
+		exitOnError(gb.SatisfyToolchain(dir))
+	case "use-external":
+		if *commitArg != "" {
+			exitOnError(gb.UseExternalCommit(versionArg, *commitArg))
+			break
+		}
+		exitOnError(gb.UseExternal(versionArg))
+	case "import":
+		switch versionArg {
+		case "goenv":
+			exitOnError(gb.ImportFromGoenv())
+		case "gvm":
+			exitOnError(gb.ImportFromGvm())
+		default:
+			exitOnError(fmt.Errorf("cannot import from %q, supported are goenv and gvm", versionArg))
+		}
 	case "uninstall":
 		gb.Uninstall(versionArg)
+	case "verify":
+		if versionArg != "" {
+			exitOnError(gb.Verify(versionArg))
+			utils.ColorSuccess.Printf("[Success] Version: %s verified\n", versionArg)
+			break
+		}
+		failures, err := gb.VerifyAll()
+		exitOnError(err)
+		for _, err := range failures {
+			utils.ColorError.Printf("[Error]: %s\n", err)
+		}
+		if len(failures) > 0 {
+			os.Exit(1)
+		}
+		utils.ColorSuccess.Println("[Success] All versions verified")
+	case "doctor":
+		diagnostics, err := gb.Doctor()
+		exitOnError(err)
+		if *networkArg {
+			diagnostics = append(diagnostics, gb.NetworkDiagnostic())
+		}
+		if *jsonArg {
+			exitOnError(gobrew.WriteDiagnosticsJSON(os.Stdout, diagnostics))
+		} else {
+			gb.PrintDiagnostics(diagnostics)
+		}
+		for _, d := range diagnostics {
+			if d.Severity == gobrew.SeverityError {
+				os.Exit(1)
+			}
+		}
+	case "prune":
+		if *keepArg < 0 {
+			exitOnError(fmt.Errorf("--keep must not be negative, got %d", *keepArg))
+		}
+		_, err := gb.PruneKeep(*keepArg, *dryRunArg)
+		exitOnError(err)
+	case "resolve":
+		version, err := gb.ResolveConstraint(versionArg)
+		exitOnError(err)
+		fmt.Println(version)
+	case "builds":
+		builds, err := gb.AvailableBuilds(versionArg)
+		exitOnError(err)
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "OS\tARCH\tKIND\tSIZE\tSHA256")
+		for _, b := range builds {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", b.OS, b.Arch, b.Kind, utils.HumanSize(b.Size), b.SHA256)
+		}
+		tw.Flush()
+	case "exec":
+		exitOnError(gb.Exec(versionArg, execArgs(args)))
+	case "url":
+		url, err := gb.DownloadURL(versionArg)
+		exitOnError(err)
+		fmt.Println(url)
+	case "notes":
+		url := gobrew.ReleaseNotesURL(versionArg)
+		if url == "" {
+			exitOnError(fmt.Errorf("invalid go version %q", versionArg))
+		}
+		fmt.Println(url)
+	case "seed-mirror":
+		if len(args) < 3 {
+			exitOnError(fmt.Errorf("usage: gobrew seed-mirror <dir> <version>..."))
+		}
+		exitOnError(gb.SeedMirror(args[2:], versionArg))
+	case "shellenv":
+		out, err := gb.ShellEnv()
+		exitOnError(err)
+		fmt.Print(out)
+	case "which":
+		goBin, err := gb.Which()
+		exitOnError(err)
+		fmt.Println(goBin)
+	case "goroot":
+		goroot, err := gb.Goroot()
+		exitOnError(err)
+		fmt.Println(goroot)
+	case "vars":
+		out, err := gb.Vars()
+		exitOnError(err)
+		fmt.Print(out)
+	case "hook":
+		out, err := gobrew.HookCommand(versionArg)
+		exitOnError(err)
+		fmt.Print(out)
+	case "ensure-path":
+		exitOnError(gb.EnsurePath())
+	case "alias":
+		if len(args) < 3 {
+			aliases, err := gb.Aliases()
+			exitOnError(err)
+			for name, version := range aliases {
+				fmt.Printf("%s -> %s\n", name, version)
+			}
+			break
+		}
+		exitOnError(gb.Alias(args[1], args[2]))
+		utils.ColorSuccess.Printf("[Success] Alias %s -> %s\n", args[1], args[2])
+	case "unalias":
+		exitOnError(gb.Unalias(versionArg))
+	case "default":
+		if versionArg == "" {
+			dv, err := gb.DefaultVersion()
+			exitOnError(err)
+			fmt.Println(dv)
+			break
+		}
+		exitOnError(gb.SetDefault(versionArg))
+		utils.ColorSuccess.Printf("[Success] Default version: %s\n", versionArg)
+	case "protect":
+		if versionArg == "" {
+			protected, err := gb.ListProtected()
+			exitOnError(err)
+			for _, version := range protected {
+				fmt.Println(version)
+			}
+			break
+		}
+		exitOnError(gb.Protect(versionArg))
+		utils.ColorSuccess.Printf("[Success] Version: %s protected from prune\n", versionArg)
+	case "unprotect":
+		exitOnError(gb.Unprotect(versionArg))
+		utils.ColorSuccess.Printf("[Success] Version: %s no longer protected\n", versionArg)
+	case "check-update":
+		current, latest, upToDate, err := gb.CheckUpdate()
+		exitOnError(err)
+		if upToDate {
+			utils.ColorSuccess.Printf("[Success] You are up to date (%s)\n", current)
+		} else {
+			utils.ColorInfo.Printf("[Info] %s available (current %s)\n", latest, current)
+		}
+	case "upgrade-all":
+		if *removeSupersededArg {
+			gb.EnableRemoveSuperseded()
+		}
+		exitOnError(gb.UpgradeAll())
+	case "tool":
+		if versionArg == "" {
+			tools, err := gb.Tools()
+			exitOnError(err)
+			for _, tool := range tools {
+				fmt.Println(tool)
+			}
+			break
+		}
+		exitOnError(gb.RecordTool(versionArg))
+		utils.ColorSuccess.Printf("[Success] Recorded %s, it is installed whenever a version is used\n", versionArg)
+	case "reset":
+		exitOnError(gb.Reset())
+		utils.ColorSuccess.Println("[Success] Reset, installed versions were kept. Please use a version")
+	case "pin":
+		exitOnError(gb.Pin(versionArg))
+		utils.ColorSuccess.Println("[Success] Pinned go version in .go-version")
+	case "config":
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "SETTING\tVALUE\tSOURCE")
+		for _, s := range gb.EffectiveConfig() {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", s.Name, s.Value, s.Source)
+		}
+		tw.Flush()
+	case "version":
+		fmt.Println(gobrew.Version())
 	case "self-update":
 		fmt.Println("Please execute curl cmd for self update")
 		fmt.Println("========================================")
@@ -64,6 +398,39 @@ func main() {
 	}
 }
 
+// installFrom installs version from an archive url or local file
+func installFrom(gb *gobrew.GoBrew, version string, from string, checksum string) {
+	if strings.HasPrefix(from, "http://") || strings.HasPrefix(from, "https://") {
+		exitOnError(gb.InstallFromURL(version, from, checksum))
+		return
+	}
+	exitOnError(gb.InstallFromFile(version, from, checksum))
+}
+
+// execArgs returns the command after `exec <version> [--]`
+func execArgs(args []string) []string {
+	if len(args) < 3 {
+		return nil
+	}
+	if args[2] == "--" {
+		return args[3:]
+	}
+	return args[2:]
+}
+
+// recordMetric records the command run, set once gobrew is set up. The
+// deferred call is skipped by os.Exit, so exitOnError calls it first
+var recordMetric = func() {}
+
+// exitOnError prints err and exits with a non-zero status
+func exitOnError(err error) {
+	if err != nil {
+		utils.ColorError.Printf("[Error]: %s\n", err)
+		recordMetric()
+		os.Exit(1)
+	}
+}
+
 func isArgAllowed() bool {
 	ok := true
 	if len(os.Args) > 1 {
@@ -96,16 +463,73 @@ func Find(slice []string, val string) (int, bool) {
 
 func usage() string {
 	msg := `
-gobrew 1.5.2
+gobrew ` + gobrew.Version().Version + `
 
 Usage:
     gobrew help                         Show this message
-    gobrew use <version>                Use <version>
+    gobrew use <version>                Use <version>, installing it first when missing
+    gobrew use <version> --install=false
+                                        Use <version>, failing when it is not installed
+    gobrew use system                   Stop using a gobrew version, go is then the one further down PATH
+    gobrew use <version> --prefix <name>
+                                        Link <version> as <root>/<name>/bin, next to the current version
+    gobrew use <version> --temporary -- <cmd>
+                                        Use <version> while <cmd> runs, then restore the current version
+    gobrew use-previous                 Use the version used before the current one
+    gobrew use-auto                     Use the version from GOBREW_GO_VERSION, .go-version, .tool-versions, go.work or go.mod
+    gobrew use-toolchain [<dir>]        Install and use the toolchain required by the go.mod in <dir>
+    gobrew use-external <goroot>        Use a GOROOT outside of gobrew, e.g. Go built from source
+    gobrew use-external <goroot> --commit <hash>
+                                        Use a GOROOT built from <hash>, as version commit-<hash>
+    gobrew import <goenv|gvm>           Make the versions installed by goenv or gvm gobrew managed
     gobrew install <version>            Download and install <version> (from binary))
+    gobrew install <version> --from <url|file> [--checksum <sha256>]
+                                        Install <version> from a custom archive, commit-<hash> for a build of a commit
+    gobrew install <version> --verify-existing
+                                        Reinstall <version> when the existing install is corrupt
+    gobrew install <version> --keep-downloads
+                                        Keep the downloaded archive for reinstalls
     gobrew uninstall <version>          Uninstall <version>
+    gobrew resolve <constraint>         Print the newest remote version matching <constraint>, e.g. ">=1.20, <1.22"
+    gobrew url <version>                Print the archive url <version> is installed from, without installing
+    gobrew notes <version>              Print the release notes url of the minor line of <version>
+    gobrew seed-mirror <dir> <version>...
+                                        Download and verify the archives of <version>s into <dir>, to serve as GOBREW_REGISTRY
+    gobrew builds <version>             List the os/arch builds published for <version>
+    gobrew prune [--keep <n>] [--dry-run]
+                                        Uninstall every version but the current, the protected and <n> newest
+    gobrew protect [<version>]          Keep <version> from being pruned, or list protected versions
+    gobrew unprotect <version>          Let prune remove <version> again
+    gobrew tool [<package>]             Record <package> to go install on every use, or list the recorded tools
+    gobrew reset                        Remove current, downloads and broken links, keeping installed versions
+    gobrew verify [<version>]           Verify <version> (or every installed version) is intact
+    gobrew doctor [--json] [--network]  Diagnose the root, the current version, PATH and installed versions
+    gobrew exec <version> -- <cmd>      Run <cmd> with <version> without changing the current version
+    gobrew shellenv                     Print exports for PATH and the env file of the current version
+    gobrew which                        Print the go binary of the current version, e.g. GO := $(shell gobrew which)
+    gobrew goroot                       Print the GOROOT of the current version
+    gobrew vars                         Print GO= and GOROOT= of the current version, without export
+    gobrew hook <bash|zsh>              Print a cd wrapper running use-auto in directories with a .go-version
+    gobrew ensure-path                  Add the current version to PATH in the rc file of your bash, zsh or fish
+    gobrew alias [<name> <version>]     Name an installed version, usable with use, or list aliases
+    gobrew unalias <name>               Remove an alias
+    gobrew default [<version>]          Record <version> as default (use default), or print it
+    gobrew check-update                 Check whether a newer stable version than the current is available
+    gobrew upgrade-all [--remove-superseded]
+                                        Install the latest patch of every installed minor line
+    gobrew pin [<version>]              Pin <version> (or the current version) in ./.go-version
     gobrew list                         List installed versions
     gobrew ls                           Alias for list
+    gobrew list --plain                 List installed version names only, one per line, for scripts
+    gobrew list --grouped               List installed versions grouped by minor line
+    gobrew list --table                 List installed versions with their size and installed date
+    gobrew list --outdated              List installed versions with a newer patch released, as <installed> -> <latest>
+    gobrew list --format <template>     List installed versions through a template, e.g. '{{.Version}} {{.Current}}'
     gobrew ls-remote                   	List remote versions (including rc|beta versions)
+    gobrew ls-remote --details          List remote versions with the size and sha256 of the archive for this platform
+    gobrew ls-remote --host             List remote versions with an archive for this platform
+    gobrew config                       Print the resolved settings and the env var, file or default each came from
+    gobrew version                      Print the version of gobrew, its commit, build date and go version
     gobrew self-update                 	Self update this tool
 
 Example:
@@ -0,0 +1,44 @@
+package gobrew
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/kevincobain2000/gobrew/utils"
+)
+
+// commitPrefix names builds of a go commit, e.g. from gotip-like flows, which
+// have no release name
+const commitPrefix = "commit-"
+
+// reCommitHash matches an abbreviated or full git commit hash
+var reCommitHash = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
+// CommitVersion returns the version name of a build of commit hash, usable
+// with InstallFromURL, InstallFromFile, UseExternalCommit and Use
+func CommitVersion(hash string) (string, error) {
+	hash = strings.ToLower(strings.TrimPrefix(hash, commitPrefix))
+	if !reCommitHash.MatchString(hash) {
+		return "", fmt.Errorf("invalid commit hash %q, expected 7 to 40 hex digits", hash)
+	}
+	return commitPrefix + hash, nil
+}
+
+// isCommitVersion tells whether version names a commit build
+func isCommitVersion(version string) bool {
+	return strings.HasPrefix(strings.ToLower(version), commitPrefix)
+}
+
+// installCommit only reports on an installed commit build, those are never
+// published to the registry
+func (gb *GoBrew) installCommit(version string) (InstallResult, error) {
+	version = gb.versionName(version)
+	if gb.existsVersion(version) {
+		utils.ColorInfo.Fprintf(gb.stdout, "[Info] Version: %s exists \n", version)
+		return InstallResult{Version: version, AlreadyInstalled: true}, nil
+	}
+	err := fmt.Errorf("version %s is a commit build, install it with --from <url|file> or use-external --commit", version)
+	utils.ColorError.Fprintf(gb.stderr, "[Error] Version: %s is a commit build, install it with --from <url|file> or use-external --commit\n", version)
+	return InstallResult{Version: version}, err
+}
@@ -0,0 +1,87 @@
+package gobrew
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCommitVersion(t *testing.T) {
+	valid := map[string]string{
+		"abc1234":            "commit-abc1234",
+		"ABC1234DEF":         "commit-abc1234def",
+		"commit-0123456789a": "commit-0123456789a",
+	}
+	for hash, want := range valid {
+		got, err := CommitVersion(hash)
+		if err != nil || got != want {
+			t.Errorf("CommitVersion(%q): expected %q, got %q, %v", hash, want, got, err)
+		}
+	}
+	for _, hash := range []string{"", "abc12", "xyz1234", "abc1234-dirty"} {
+		if got, err := CommitVersion(hash); err == nil {
+			t.Errorf("CommitVersion(%q): expected an error, got %q", hash, got)
+		}
+	}
+}
+
+func TestUseCommitVersion(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	installFakeVersion(t, gb, "1.16", "go version go1.16 linux/amd64")
+	os.MkdirAll(gb.currentDir, os.ModePerm)
+	gb.Use("1.16")
+	archive := filepath.Join(tempDir(t), "go-tip.tar.gz")
+	writeTarGz(t, archive, map[string]string{"go/bin/go": "go"})
+
+	if err := gb.InstallFromFile("commit-zzz", archive, ""); err == nil {
+		t.Error("expected an error installing an invalid commit version")
+	}
+	if err := gb.InstallFromFile("commit-ABC1234", archive, ""); err != nil {
+		t.Fatal(err)
+	}
+	if !gb.existsVersion("commit-abc1234") {
+		t.Fatal("expected the commit build installed as commit-abc1234")
+	}
+	if result := gb.Install("commit-abc1234"); !result.AlreadyInstalled {
+		t.Errorf("expected the installed commit build reported as already installed, got %+v", result)
+	}
+
+	installed, _ := gb.InstalledVersions()
+	if !reflect.DeepEqual(installed, []string{"1.16", "commit-abc1234"}) {
+		t.Errorf("expected the commit build listed after 1.16, got %v", installed)
+	}
+
+	gb.Use("commit-ABC1234")
+	if cv := gb.CurrentVersion(); cv != "commit-abc1234" {
+		t.Errorf("expected commit-abc1234 to be current, got %q", cv)
+	}
+	gb.Use("1.16")
+	if cv := gb.CurrentVersion(); cv != "1.16" {
+		t.Errorf("expected 1.16 to be current again, got %q", cv)
+	}
+}
+
+func TestUseExternalCommit(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	goroot := filepath.Join(tempDir(t), "go")
+	os.MkdirAll(filepath.Join(goroot, "bin"), os.ModePerm)
+	if err := ioutil.WriteFile(filepath.Join(goroot, "bin", "go"), []byte("#!/bin/sh\necho go version devel\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := gb.UseExternalCommit(goroot, "nothex"); err == nil {
+		t.Error("expected an error for an invalid commit hash")
+	}
+	if err := gb.UseExternalCommit(goroot, "DEADBEEF"); err != nil {
+		t.Fatal(err)
+	}
+	if cv := gb.CurrentVersion(); cv != "commit-deadbeef" {
+		t.Errorf("expected commit-deadbeef to be current, got %q", cv)
+	}
+	installed, _ := gb.InstalledVersions()
+	if !reflect.DeepEqual(installed, []string{"commit-deadbeef"}) {
+		t.Errorf("expected commit-deadbeef to be listed, got %v", installed)
+	}
+}
@@ -0,0 +1,106 @@
+package gobrew
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Sources of a Setting that isn't read from an env var
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+)
+
+// Setting is one resolved configuration value and where it came from: the
+// env var that set it, the state file it was read from or the default
+type Setting struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// Config is the configuration NewGoBrew resolved, in a stable order
+type Config []Setting
+
+// Lookup returns the setting called name
+func (c Config) Lookup(name string) (Setting, bool) {
+	for _, s := range c {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Setting{}, false
+}
+
+// EffectiveConfig reports the settings gobrew runs with, each with the env
+// var NewGoBrew read it from or the default
+func (gb *GoBrew) EffectiveConfig() Config {
+	setting := func(name string, value string, env string) Setting {
+		s := Setting{Name: name, Value: value, Source: SourceDefault}
+		if gb.envSources[env] {
+			s.Source = env
+		}
+		return s
+	}
+	systemRoot := ""
+	if gb.systemVersionsDir != "" {
+		systemRoot = filepath.Dir(gb.systemVersionsDir)
+	}
+	strip := strconv.Itoa(gb.stripComponents)
+	if gb.stripComponents < 0 {
+		strip = "detected"
+	}
+	config := Config{
+		setting("root", gb.installDir, "GOBREW_ROOT"),
+		setting("system root", systemRoot, "GOBREW_SYSTEM_ROOT"),
+		setting("bin subpath", gb.binSubpath, "GOBREW_BIN_SUBPATH"),
+		setting("strip components", strip, "GOBREW_STRIP_COMPONENTS"),
+		setting("extract workers", strconv.Itoa(gb.extractWorkers), "GOBREW_EXTRACT_WORKERS"),
+		setting("minimal", strconv.FormatBool(gb.minimal), "GOBREW_MINIMAL"),
+		setting("downloads", gb.downloadsDir, "GOBREW_DOWNLOAD_DIR"),
+		setting("registry", gb.registryPath, "GOBREW_REGISTRY"),
+		setting("mirrors", strings.Join(gb.mirrors, ","), "GOBREW_MIRRORS"),
+		setting("downloader", gb.downloader, "GOBREW_DOWNLOADER"),
+		setting("tags repo", gb.tagsRepo, "GOBREW_TAGS_REPO"),
+		setting("releases url", gb.releasesURL, "GOBREW_RELEASES_URL"),
+		setting("git timeout", gb.gitTimeout.String(), "GOBREW_GIT_TIMEOUT"),
+		setting("cache ttl", gb.releasesCacheTTL.String(), "GOBREW_CACHE_TTL"),
+		setting("download max age", gb.downloadMaxAge.String(), "GOBREW_DOWNLOAD_MAX_AGE"),
+		setting("download cache max", strconv.FormatInt(gb.downloadCacheMax, 10), "GOBREW_DOWNLOAD_CACHE_MAX"),
+		setting("tmpdir", gb.tmpDir, "GOBREW_TMPDIR"),
+		setting("offline", strconv.FormatBool(gb.offline), "GOBREW_NO_NETWORK"),
+		setting("auto install", strconv.FormatBool(gb.autoInstall), "GOBREW_AUTO_INSTALL"),
+		setting("keep downloads", strconv.FormatBool(gb.keepDownloads), "GOBREW_KEEP_DOWNLOADS"),
+		setting("gotoolchain", gb.goToolchain, "GOBREW_GOTOOLCHAIN"),
+		setting("metrics", strconv.FormatBool(gb.metrics), "GOBREW_METRICS"),
+		setting("debug", strconv.FormatBool(gb.debug != nil && gb.debug.Writer() != ioutil.Discard), "GOBREW_DEBUG"),
+	}
+
+	def := Setting{Name: "default version", Source: SourceDefault}
+	if version, err := gb.DefaultVersion(); err == nil && version != "" {
+		def.Value = version
+		def.Source = SourceFile + " " + filepath.Join(gb.installDir, defaultFile)
+	}
+	return append(config, def)
+}
+
+// fromEnv records env as the source of the setting NewGoBrew just read from it
+func (gb *GoBrew) fromEnv(env string) {
+	if gb.envSources == nil {
+		gb.envSources = make(map[string]bool)
+	}
+	gb.envSources[env] = true
+}
+
+// envFlag reads an env var set to 1 to turn a setting on, any other value
+// set still counts as its source
+func (gb *GoBrew) envFlag(env string) bool {
+	value := os.Getenv(env)
+	if value != "" {
+		gb.fromEnv(env)
+	}
+	return value == "1"
+}
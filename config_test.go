@@ -0,0 +1,56 @@
+package gobrew
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEffectiveConfig(t *testing.T) {
+	root := tempDir(t)
+	os.Setenv("GOBREW_ROOT", root)
+	defer os.Unsetenv("GOBREW_ROOT")
+	os.Setenv("GOBREW_REGISTRY", "https://mirror.example.com/go/")
+	defer os.Unsetenv("GOBREW_REGISTRY")
+	os.Setenv("GOBREW_GIT_TIMEOUT", "2m")
+	defer os.Unsetenv("GOBREW_GIT_TIMEOUT")
+	// set to its default, still reported as set
+	os.Setenv("GOBREW_CACHE_TTL", "1h")
+	defer os.Unsetenv("GOBREW_CACHE_TTL")
+	os.Setenv("GOBREW_EXTRACT_WORKERS", "4")
+	defer os.Unsetenv("GOBREW_EXTRACT_WORKERS")
+	os.Setenv("GOBREW_MINIMAL", "1")
+	defer os.Unsetenv("GOBREW_MINIMAL")
+	ioutil.WriteFile(filepath.Join(root, defaultFile), []byte("1.17\n"), 0644)
+
+	gb, err := NewGoBrew()
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := gb.EffectiveConfig()
+
+	for _, want := range []Setting{
+		{Name: "root", Value: root, Source: "GOBREW_ROOT"},
+		{Name: "registry", Value: "https://mirror.example.com/go/", Source: "GOBREW_REGISTRY"},
+		{Name: "git timeout", Value: "2m0s", Source: "GOBREW_GIT_TIMEOUT"},
+		{Name: "cache ttl", Value: "1h0m0s", Source: "GOBREW_CACHE_TTL"},
+		{Name: "extract workers", Value: "4", Source: "GOBREW_EXTRACT_WORKERS"},
+		{Name: "minimal", Value: "true", Source: "GOBREW_MINIMAL"},
+		{Name: "strip components", Value: "detected", Source: SourceDefault},
+		{Name: "metrics", Value: "false", Source: SourceDefault},
+		{Name: "debug", Value: "false", Source: SourceDefault},
+		{Name: "tags repo", Value: defaultTagsRepo, Source: SourceDefault},
+		{Name: "downloads", Value: filepath.Join(root, "downloads"), Source: SourceDefault},
+		{Name: "default version", Value: "1.17", Source: SourceFile + " " + filepath.Join(root, defaultFile)},
+	} {
+		got, ok := config.Lookup(want.Name)
+		if !ok {
+			t.Errorf("missing setting %s", want.Name)
+			continue
+		}
+		if got != want {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package gobrew
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/kevincobain2000/gobrew/utils"
+)
+
+const connectivityTimeout = 10 * time.Second
+
+// CheckConnectivity tells network problems apart from gobrew ones: it sends
+// a HEAD to the registry and asks the tags repo for HEAD, reporting how long
+// each took
+func (gb *GoBrew) CheckConnectivity() error {
+	if gb.offline {
+		return ErrOfflineMode
+	}
+	if err := gb.pingRegistry(); err != nil {
+		return err
+	}
+	return gb.pingTagsRepo()
+}
+
+func (gb *GoBrew) pingRegistry() error {
+	req, err := http.NewRequest(http.MethodHead, gb.registryPath, nil)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: connectivityTimeout}
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("registry %s is unreachable: %w", gb.registryPath, err)
+	}
+	resp.Body.Close()
+	latency := time.Since(start).Round(time.Millisecond)
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("registry %s answered %s", gb.registryPath, resp.Status)
+	}
+	utils.ColorInfo.Fprintf(gb.stdout, "[Info] registry %s reachable in %s\n", gb.registryPath, latency)
+	return nil
+}
+
+func (gb *GoBrew) pingTagsRepo() error {
+	ctx, cancel := context.WithTimeout(context.Background(), gb.gitTimeout)
+	defer cancel()
+	start := time.Now()
+	if _, err := gb.git(ctx, "ls-remote", gb.tagsRepo, "HEAD"); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("%w: tags repo %s did not answer within %s", ErrTimeout, gb.tagsRepo, gb.gitTimeout)
+		}
+		return fmt.Errorf("tags repo %s is unreachable: %w", gb.tagsRepo, err)
+	}
+	latency := time.Since(start).Round(time.Millisecond)
+	utils.ColorInfo.Fprintf(gb.stdout, "[Info] tags repo %s reachable in %s\n", gb.tagsRepo, latency)
+	return nil
+}
@@ -0,0 +1,49 @@
+package gobrew
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckConnectivity(t *testing.T) {
+	var method string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+	}))
+	defer ts.Close()
+	fakeBin(t, "git", "exit 0\n")
+
+	gb := newTestGoBrew(tempDir(t))
+	gb.registryPath = ts.URL + "/"
+	if err := gb.CheckConnectivity(); err != nil {
+		t.Fatal(err)
+	}
+	if method != http.MethodHead {
+		t.Errorf("expected a HEAD request, got %q", method)
+	}
+}
+
+func TestCheckConnectivityUnreachable(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	ts.Close()
+	fakeBin(t, "git", "exit 0\n")
+
+	gb := newTestGoBrew(tempDir(t))
+	gb.registryPath = ts.URL + "/"
+	if err := gb.CheckConnectivity(); err == nil {
+		t.Error("expected an error for a closed registry")
+	}
+}
+
+func TestCheckConnectivityTagsRepo(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+	fakeBin(t, "git", "echo 'fatal: unable to access' >&2\nexit 128\n")
+
+	gb := newTestGoBrew(tempDir(t))
+	gb.registryPath = ts.URL + "/"
+	if err := gb.CheckConnectivity(); err == nil {
+		t.Error("expected an error for an unreachable tags repo")
+	}
+}
@@ -0,0 +1,41 @@
+package gobrew
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver"
+)
+
+// ResolveConstraint returns the newest remote release matching a semver
+// constraint like ">=1.20, <1.22" or "~1.21", without installing anything.
+// Prereleases never match
+func (gb *GoBrew) ResolveConstraint(constraint string) (string, error) {
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return "", fmt.Errorf("invalid constraint %q: %w", constraint, err)
+	}
+	versions, err := gb.RemoteVersions()
+	if err != nil {
+		return "", err
+	}
+	if version, ok := newestMatch(c, versions); ok {
+		return version, nil
+	}
+	return "", fmt.Errorf("no remote version satisfies %q", constraint)
+}
+
+// newestMatch returns the newest release in versions that satisfies c
+func newestMatch(c *semver.Constraints, versions []string) (string, bool) {
+	sorted := append([]string(nil), versions...)
+	sortVersions(sorted)
+	for i := len(sorted) - 1; i >= 0; i-- {
+		if v, ok := parseGoVersion(sorted[i]); !ok || v.pre != "" {
+			continue
+		}
+		sv, err := semver.NewVersion(sorted[i])
+		if err == nil && c.Check(sv) {
+			return sorted[i], true
+		}
+	}
+	return "", false
+}
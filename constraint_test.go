@@ -0,0 +1,33 @@
+package gobrew
+
+import (
+	"testing"
+)
+
+func TestResolveConstraint(t *testing.T) {
+	fakeBin(t, "git", `for v in 1.19.13 1.20 1.20.14 1.21rc2 1.21.0 1.21.6 1.22rc1; do
+	printf 'abc\trefs/tags/go%s\n' $v
+done
+`)
+	gb := newTestGoBrew(tempDir(t))
+
+	tests := []struct {
+		constraint string
+		want       string
+		wantErr    bool
+	}{
+		{constraint: ">=1.20,<1.22", want: "1.21.6"},
+		{constraint: ">=1.20, <1.21", want: "1.20.14"},
+		{constraint: "~1.19", want: "1.19.13"},
+		{constraint: "1.20", want: "1.20"},
+		{constraint: ">=1.22", wantErr: true},
+		{constraint: "<1.19", wantErr: true},
+		{constraint: "not a constraint", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := gb.ResolveConstraint(tt.constraint)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ResolveConstraint(%q) = (%q, %v), want %q", tt.constraint, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package gobrew
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/kevincobain2000/gobrew/utils"
+)
+
+// systemVersion is the version name meaning no gobrew version: the go found
+// further down PATH, e.g. from the OS packages or /usr/local/go. Not to be
+// confused with GOBREW_SYSTEM_ROOT
+const systemVersion = "system"
+
+// systemSelectedFile records that the system go was picked with `use system`
+const systemSelectedFile = "system"
+
+// useSystem removes the current links so PATH falls through to the system go
+func (gb *GoBrew) useSystem() {
+	previous := gb.CurrentVersion()
+	if previous == systemVersion {
+		utils.ColorInfo.Fprintf(gb.stdout, "[Info] Version: %s is already your current version \n", systemVersion)
+		return
+	}
+	err := gb.withCurrentLock(func() error {
+		os.RemoveAll(gb.currentBinDir)
+		os.RemoveAll(gb.currentGoDir)
+		if err := ioutil.WriteFile(filepath.Join(gb.installDir, systemSelectedFile), nil, 0644); err != nil {
+			return fmt.Errorf("recording the system version: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		utils.ColorError.Fprintf(gb.stderr, "[Error] %s\n", err)
+		osExit(1)
+		return
+	}
+	if previous != "" {
+		if err := gb.setPreviousVersion(previous); err != nil {
+			gb.debug.Printf("recording the previous version: %s", err)
+		}
+	}
+	utils.ColorSuccess.Fprintf(gb.stdout, "[Success] Changed go version to: %s, go is now the one found on PATH after %s\n", systemVersion, gb.currentBinDir)
+}
+
+// systemSelected reports whether `use system` is in effect
+func (gb *GoBrew) systemSelected() bool {
+	_, err := os.Stat(filepath.Join(gb.installDir, systemSelectedFile))
+	return err == nil
+}
+
+// clearSystemSelected is called when a gobrew version is used again
+func (gb *GoBrew) clearSystemSelected() {
+	os.Remove(filepath.Join(gb.installDir, systemSelectedFile))
+}
@@ -0,0 +1,46 @@
+package gobrew
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUseSystem(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	installFakeVersion(t, gb, "1.16", "go version go1.16 linux/amd64")
+	useFixture(t, gb, "1.16")
+
+	gb.Use("system")
+
+	for _, link := range []string{gb.currentBinDir, gb.currentGoDir} {
+		if _, err := os.Lstat(link); !os.IsNotExist(err) {
+			t.Errorf("expected %s removed, got %v", link, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(gb.installDir, systemSelectedFile)); err != nil {
+		t.Errorf("expected system recorded: %s", err)
+	}
+	if cv := gb.CurrentVersion(); cv != systemVersion {
+		t.Errorf("expected current version system, got %q", cv)
+	}
+	if previous, _ := gb.PreviousVersion(); previous != "1.16" {
+		t.Errorf("expected 1.16 recorded as previous, got %q", previous)
+	}
+
+	if err := gb.UsePrevious(); err != nil {
+		t.Fatal(err)
+	}
+	if cv := gb.CurrentVersion(); cv != "1.16" {
+		t.Errorf("expected back to 1.16, got %q", cv)
+	}
+	if gb.systemSelected() {
+		t.Error("expected system no longer recorded once a version is used")
+	}
+	if err := gb.UsePrevious(); err != nil {
+		t.Fatal(err)
+	}
+	if cv := gb.CurrentVersion(); cv != systemVersion {
+		t.Errorf("expected back to system, got %q", cv)
+	}
+}
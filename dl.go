@@ -0,0 +1,228 @@
+package gobrew
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultReleasesURL lists every go release with its published files
+const defaultReleasesURL string = "https://go.dev/dl/?mode=json&include=all"
+
+// releasesTimeout bounds fetching the release list, a stalled go.dev must not
+// hang every command that needs it
+var releasesTimeout = 30 * time.Second
+
+// Release as published by the go.dev/dl JSON API
+type Release struct {
+	Version string `json:"version"`
+	Stable  bool   `json:"stable"`
+	// Date the release was published, zero when the API doesn't say
+	Date  time.Time `json:"date,omitempty"`
+	Files []Build   `json:"files"`
+}
+
+// Build is a single file published for a release
+type Build struct {
+	Filename string `json:"filename"`
+	OS       string `json:"os"`
+	Arch     string `json:"arch"`
+	Version  string `json:"version"`
+	SHA256   string `json:"sha256"`
+	Size     int64  `json:"size"`
+	Kind     string `json:"kind"`
+}
+
+// fetchReleases reads every release from the JSON API, newest first. The
+// response is cached for releasesCacheTTL and then revalidated with its ETag
+// and Last-Modified, a 304 keeps using the cache. Offline, any cache is used
+func (gb *GoBrew) fetchReleases() ([]Release, error) {
+	cache := gb.readReleasesCache()
+	if cache != nil && (gb.offline || time.Since(cache.FetchedAt) < gb.releasesCacheTTL) {
+		gb.debug.Printf("using releases cached at %s", cache.FetchedAt)
+		return cache.Releases, nil
+	}
+	if gb.offline {
+		return nil, fmt.Errorf("%w: no cached releases", ErrOfflineMode)
+	}
+
+	gb.debug.Printf("fetching %s", gb.releasesURL)
+	req, err := http.NewRequest(http.MethodGet, gb.releasesURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if cache != nil {
+		if cache.ETag != "" {
+			req.Header.Set("If-None-Match", cache.ETag)
+		}
+		if cache.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cache.LastModified)
+		}
+	}
+	client := &http.Client{Timeout: releasesTimeout}
+	resp, err := client.Do(req)
+	if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+		return nil, fmt.Errorf("%w: %s did not answer within %s", ErrTimeout, gb.releasesURL, releasesTimeout)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified && cache != nil {
+		gb.debug.Printf("releases not modified since %s", cache.FetchedAt)
+		cache.FetchedAt = time.Now()
+		gb.writeReleasesCache(cache)
+		return cache.Releases, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: response status code %d", gb.releasesURL, resp.StatusCode)
+	}
+	releases, err := decodeReleases(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	gb.writeReleasesCache(&releasesCache{
+		URL:          gb.releasesURL,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		FetchedAt:    time.Now(),
+		Releases:     releases,
+	})
+	return releases, nil
+}
+
+func decodeReleases(r io.Reader) ([]Release, error) {
+	releases := make([]Release, 0)
+	if err := json.NewDecoder(r).Decode(&releases); err != nil {
+		return nil, fmt.Errorf("decoding releases: %s", err)
+	}
+	return releases, nil
+}
+
+// findRelease returns the release named version, e.g. 1.17.6
+func findRelease(releases []Release, version string) (Release, bool) {
+	for _, release := range releases {
+		if release.Version == "go"+version {
+			return release, true
+		}
+	}
+	return Release{}, false
+}
+
+// AvailableBuilds lists the files published for version on every os and arch
+func (gb *GoBrew) AvailableBuilds(version string) ([]Build, error) {
+	releases, err := gb.fetchReleases()
+	if err != nil {
+		return nil, err
+	}
+	release, ok := findRelease(releases, version)
+	if !ok {
+		return nil, fmt.Errorf("version %s has not been released", version)
+	}
+	return release.Files, nil
+}
+
+// latestStable returns the newest stable version, e.g. 1.17.6, from a single
+// JSON API call. Releases come newest first, so it is the first stable one.
+func (gb *GoBrew) latestStable() (string, error) {
+	releases, err := gb.fetchReleases()
+	if err != nil {
+		return "", err
+	}
+	return firstStable(releases)
+}
+
+func firstStable(releases []Release) (string, error) {
+	for _, release := range releases {
+		if release.Stable {
+			return strings.TrimPrefix(release.Version, "go"), nil
+		}
+	}
+	return "", fmt.Errorf("no stable release found")
+}
+
+// releasedVersions lists the name of every release in the JSON API
+func (gb *GoBrew) releasedVersions() ([]string, error) {
+	releases, err := gb.fetchReleases()
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]string, 0, len(releases))
+	for _, release := range releases {
+		versions = append(versions, strings.TrimPrefix(release.Version, "go"))
+	}
+	return versions, nil
+}
+
+// RemoteVersion is a release with the size and checksum of its archive for
+// the host platform, both empty when it publishes none
+type RemoteVersion struct {
+	Version string
+	Stable  bool
+	Size    int64
+	SHA256  string
+}
+
+// RemoteVersionsWithBuilds lists every release from the JSON API with the
+// details of its archive for the host platform, oldest first
+func (gb *GoBrew) RemoteVersionsWithBuilds() ([]RemoteVersion, error) {
+	releases, err := gb.fetchReleases()
+	if err != nil {
+		return nil, err
+	}
+	return remoteVersionsFor(releases, runtime.GOOS, archiveArch(runtime.GOOS, runtime.GOARCH)), nil
+}
+
+func remoteVersionsFor(releases []Release, goos string, goarch string) []RemoteVersion {
+	versions := make([]RemoteVersion, 0, len(releases))
+	for _, release := range releases {
+		rv := RemoteVersion{Version: strings.TrimPrefix(release.Version, "go"), Stable: release.Stable}
+		if build, ok := hostArchive(release, goos, goarch); ok {
+			rv.Size = build.Size
+			rv.SHA256 = build.SHA256
+		}
+		versions = append(versions, rv)
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return compareVersions(versions[i].Version, versions[j].Version) < 0
+	})
+	return versions
+}
+
+// RemoteVersionsForHost lists the releases from the JSON API that publish an
+// archive for the host platform, oldest first. Older versions often have no
+// build for less common platforms such as linux/riscv64
+func (gb *GoBrew) RemoteVersionsForHost() ([]string, error) {
+	releases, err := gb.fetchReleases()
+	if err != nil {
+		return nil, err
+	}
+	return versionsWithArchive(releases, runtime.GOOS, archiveArch(runtime.GOOS, runtime.GOARCH)), nil
+}
+
+func versionsWithArchive(releases []Release, goos string, goarch string) []string {
+	versions := make([]string, 0, len(releases))
+	for _, release := range releases {
+		if _, ok := hostArchive(release, goos, goarch); ok {
+			versions = append(versions, strings.TrimPrefix(release.Version, "go"))
+		}
+	}
+	sortVersions(versions)
+	return versions
+}
+
+// hostArchive returns the archive release publishes for goos/goarch
+func hostArchive(release Release, goos string, goarch string) (Build, bool) {
+	for _, file := range release.Files {
+		if file.OS == goos && file.Arch == goarch && file.Kind == "archive" {
+			return file, true
+		}
+	}
+	return Build{}, false
+}
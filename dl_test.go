@@ -0,0 +1,145 @@
+package gobrew
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// releasesFixture mirrors the go.dev/dl JSON API, newest first
+const releasesFixture = `[
+  {"version": "go1.18beta1", "stable": false, "files": [
+    {"filename": "go1.18beta1.linux-amd64.tar.gz", "os": "linux", "arch": "amd64", "version": "go1.18beta1", "sha256": "aaa1", "size": 141000000, "kind": "archive"}
+  ]},
+  {"version": "go1.17.6", "stable": true, "files": [
+    {"filename": "go1.17.6.src.tar.gz", "os": "", "arch": "", "version": "go1.17.6", "sha256": "bbb0", "size": 22000000, "kind": "source"},
+    {"filename": "go1.17.6.linux-amd64.tar.gz", "os": "linux", "arch": "amd64", "version": "go1.17.6", "sha256": "bbb1", "size": 135000000, "kind": "archive"},
+    {"filename": "go1.17.6.linux-arm64.tar.gz", "os": "linux", "arch": "arm64", "version": "go1.17.6", "sha256": "bbb2", "size": 102000000, "kind": "archive"},
+    {"filename": "go1.17.6.darwin-arm64.tar.gz", "os": "darwin", "arch": "arm64", "version": "go1.17.6", "sha256": "bbb3", "size": 130000000, "kind": "archive"},
+    {"filename": "go1.17.6.windows-amd64.msi", "os": "windows", "arch": "amd64", "version": "go1.17.6", "sha256": "bbb4", "size": 120000000, "kind": "installer"}
+  ]},
+  {"version": "go1.16.13", "stable": true, "files": [
+    {"filename": "go1.16.13.linux-amd64.tar.gz", "os": "linux", "arch": "amd64", "version": "go1.16.13", "sha256": "ccc1", "size": 129000000, "kind": "archive"},
+    {"filename": "go1.16.13.darwin-amd64.tar.gz", "os": "darwin", "arch": "amd64", "version": "go1.16.13", "sha256": "ccc2", "size": 130000000, "kind": "archive"}
+  ]},
+  {"version": "go1.4.3", "stable": false, "files": [
+    {"filename": "go1.4.3.linux-amd64.tar.gz", "os": "linux", "arch": "amd64", "version": "go1.4.3", "sha256": "ddd1", "size": 62000000, "kind": "archive"}
+  ]}
+]`
+
+// serveReleases points gb at a JSON API serving body
+func serveReleases(t *testing.T, gb *GoBrew, body string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	gb.releasesURL = server.URL + "/?mode=json&include=all"
+	return server
+}
+
+func TestAvailableBuilds(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	serveReleases(t, &gb, releasesFixture)
+
+	builds, err := gb.AvailableBuilds("1.17.6")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(builds) != 5 {
+		t.Fatalf("expected the 5 files of 1.17.6, got %d", len(builds))
+	}
+	arm := builds[2]
+	if arm.OS != "linux" || arm.Arch != "arm64" || arm.Kind != "archive" || arm.SHA256 != "bbb2" || arm.Size != 102000000 {
+		t.Errorf("unexpected build %+v", arm)
+	}
+
+	if _, err := gb.AvailableBuilds("1.99"); err == nil || !strings.Contains(err.Error(), "1.99") {
+		t.Errorf("expected an error for an unreleased version, got %v", err)
+	}
+}
+
+func TestLatestStable(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	serveReleases(t, &gb, releasesFixture)
+
+	latest, err := gb.latestStable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest != "1.17.6" {
+		t.Errorf("expected 1.17.6 skipping the 1.18beta1 prerelease, got %s", latest)
+	}
+
+	serveReleases(t, &gb, `[{"version": "go1.18rc1", "stable": false, "files": []}]`)
+	if _, err := gb.latestStable(); err == nil {
+		t.Error("expected an error when only prereleases are listed")
+	}
+}
+
+func TestRemoteVersionsFor(t *testing.T) {
+	releases, err := decodeReleases(strings.NewReader(releasesFixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	versions := remoteVersionsFor(releases, "darwin", "arm64")
+	want := []RemoteVersion{
+		{Version: "1.4.3"},
+		{Version: "1.16.13", Stable: true},
+		{Version: "1.17.6", Stable: true, Size: 130000000, SHA256: "bbb3"},
+		{Version: "1.18beta1"},
+	}
+	if !reflect.DeepEqual(versions, want) {
+		t.Errorf("got %+v, want %+v", versions, want)
+	}
+
+	versions = remoteVersionsFor(releases, "linux", "arm64")
+	if versions[2].SHA256 != "bbb2" {
+		t.Errorf("expected the linux-arm64 archive of 1.17.6, got %+v", versions[2])
+	}
+}
+
+func TestVersionsWithArchive(t *testing.T) {
+	releases, err := decodeReleases(strings.NewReader(releasesFixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for platform, want := range map[string][]string{
+		"linux/amd64":   {"1.4.3", "1.16.13", "1.17.6", "1.18beta1"},
+		"darwin/arm64":  {"1.17.6"},
+		"darwin/amd64":  {"1.16.13"},
+		"windows/amd64": {},
+		"linux/riscv64": {},
+	} {
+		parts := strings.Split(platform, "/")
+		versions := versionsWithArchive(releases, parts[0], parts[1])
+		if !reflect.DeepEqual(versions, want) {
+			t.Errorf("%s: got %v, want %v", platform, versions, want)
+		}
+	}
+}
+
+func TestFetchReleasesTimeout(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(done) })
+	gb.releasesURL = server.URL + "/?mode=json&include=all"
+
+	releasesTimeout = 50 * time.Millisecond
+	defer func() { releasesTimeout = 30 * time.Second }()
+
+	if _, err := gb.fetchReleases(); !errors.Is(err, ErrTimeout) {
+		t.Errorf("expected ErrTimeout from a stalled releases API, got %v", err)
+	}
+}
@@ -0,0 +1,182 @@
+package gobrew
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kevincobain2000/gobrew/utils"
+)
+
+// Severities of a Diagnostic
+const (
+	SeverityOK      = "ok"
+	SeverityWarning = "warning"
+	SeverityError   = "error"
+)
+
+// Diagnostic is the outcome of one Doctor check, with how to fix it unless OK
+type Diagnostic struct {
+	Name     string `json:"name"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Fix      string `json:"fix,omitempty"`
+}
+
+// Doctor checks the install root, the current version, PATH and every
+// installed version for the problems gobrew knows how to fix
+func (gb *GoBrew) Doctor() ([]Diagnostic, error) {
+	diagnostics := []Diagnostic{gb.checkRoot(), gb.checkCurrent(), gb.checkSymlinks(), gb.checkPath()}
+
+	failures, err := gb.VerifyAll()
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]string, 0, len(failures))
+	for version := range failures {
+		versions = append(versions, version)
+	}
+	sortVersions(versions)
+	for _, version := range versions {
+		diagnostics = append(diagnostics, Diagnostic{
+			Name:     "version " + version,
+			Severity: SeverityError,
+			Message:  failures[version].Error(),
+			Fix:      "gobrew install " + version + " --verify-existing",
+		})
+	}
+	return append(diagnostics, gb.checkLeftovers()), nil
+}
+
+func (gb *GoBrew) checkRoot() Diagnostic {
+	d := Diagnostic{Name: "root", Severity: SeverityOK, Message: gb.installDir}
+	fi, err := os.Stat(gb.installDir)
+	if os.IsNotExist(err) {
+		d.Severity = SeverityWarning
+		d.Message = gb.installDir + " does not exist yet"
+		d.Fix = "gobrew install <version>"
+		return d
+	}
+	if err == nil && !fi.IsDir() {
+		err = fmt.Errorf("%s is not a directory", gb.installDir)
+	}
+	if err != nil {
+		d.Severity = SeverityError
+		d.Message = err.Error()
+		d.Fix = "point GOBREW_ROOT at a writable directory"
+	}
+	return d
+}
+
+func (gb *GoBrew) checkCurrent() Diagnostic {
+	d := Diagnostic{Name: "current", Severity: SeverityOK}
+	if gb.CurrentVersion() == systemVersion {
+		d.Message = systemVersion
+		return d
+	}
+	if _, err := os.Lstat(gb.currentBinDir); os.IsNotExist(err) {
+		d.Severity = SeverityWarning
+		d.Message = "no current version"
+		d.Fix = "gobrew use <version>"
+		return d
+	}
+	version := gb.CurrentVersion()
+	if version == "" {
+		d.Severity = SeverityError
+		d.Message = gb.currentBinDir + " is a broken link"
+		d.Fix = "gobrew reset && gobrew use <version>"
+		return d
+	}
+	d.Message = version
+	return d
+}
+
+func (gb *GoBrew) checkSymlinks() Diagnostic {
+	d := Diagnostic{Name: "symlinks", Severity: SeverityOK, Message: "current bin and go agree"}
+	if err := gb.CheckSymlinkConsistency(); err != nil {
+		d.Severity = SeverityError
+		d.Message = err.Error()
+		d.Fix = "gobrew use <version>"
+		if version := gb.CurrentVersion(); version != "" {
+			d.Fix = "gobrew use " + version
+		}
+	}
+	return d
+}
+
+func (gb *GoBrew) checkPath() Diagnostic {
+	d := Diagnostic{Name: "path", Severity: SeverityOK, Message: gb.currentBinDir + " is on PATH"}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if filepath.Clean(dir) == gb.currentBinDir {
+			return d
+		}
+	}
+	d.Severity = SeverityWarning
+	d.Message = gb.currentBinDir + " is not on PATH"
+	d.Fix = "gobrew ensure-path"
+	return d
+}
+
+// checkLeftovers finds what Reset cleans up: unfinished extractions and
+// dangling links in the versions dir
+func (gb *GoBrew) checkLeftovers() Diagnostic {
+	d := Diagnostic{Name: "leftovers", Severity: SeverityOK, Message: "no leftovers"}
+	entries, err := ioutil.ReadDir(gb.versionsDir)
+	if err != nil {
+		return d
+	}
+	var leftovers []string
+	for _, entry := range entries {
+		path := filepath.Join(gb.versionsDir, entry.Name())
+		if entry.IsDir() {
+			path = filepath.Join(path, "go")
+		}
+		_, err := os.Stat(path)
+		if strings.HasPrefix(entry.Name(), ".tmp-") || os.IsNotExist(err) {
+			leftovers = append(leftovers, entry.Name())
+		}
+	}
+	if len(leftovers) > 0 {
+		d.Severity = SeverityWarning
+		d.Message = "leftovers in " + gb.versionsDir + ": " + strings.Join(leftovers, ", ")
+		d.Fix = "gobrew reset"
+	}
+	return d
+}
+
+// NetworkDiagnostic reports CheckConnectivity as a Diagnostic, it is not
+// part of Doctor so that doctor works offline
+func (gb *GoBrew) NetworkDiagnostic() Diagnostic {
+	d := Diagnostic{Name: "network", Severity: SeverityOK, Message: "registry and tags repo reachable"}
+	if err := gb.CheckConnectivity(); err != nil {
+		d.Severity = SeverityError
+		d.Message = err.Error()
+		d.Fix = "check your network and proxy, or set GOBREW_REGISTRY and GOBREW_TAGS_REPO to reachable mirrors"
+	}
+	return d
+}
+
+// PrintDiagnostics writes diagnostics for people, one per line
+func (gb *GoBrew) PrintDiagnostics(diagnostics []Diagnostic) {
+	for _, d := range diagnostics {
+		switch d.Severity {
+		case SeverityOK:
+			utils.ColorSuccess.Fprintf(gb.stdout, "[Success] %s: %s\n", d.Name, d.Message)
+		case SeverityWarning:
+			utils.ColorInfo.Fprintf(gb.stdout, "[Info] %s: %s, fix with: %s\n", d.Name, d.Message, d.Fix)
+		default:
+			utils.ColorError.Fprintf(gb.stdout, "[Error] %s: %s, fix with: %s\n", d.Name, d.Message, d.Fix)
+		}
+	}
+}
+
+// WriteDiagnosticsJSON writes diagnostics as a JSON array, for editors
+func WriteDiagnosticsJSON(w io.Writer, diagnostics []Diagnostic) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(diagnostics)
+}
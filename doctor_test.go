@@ -0,0 +1,56 @@
+package gobrew
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDoctorJSON(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	installFakeVersion(t, gb, "1.16", "go version go1.16 linux/amd64")
+	installFakeVersion(t, gb, "1.17", "go version go1.17 linux/amd64")
+	os.Remove(filepath.Join(gb.getVersionDir("1.17"), "go", "bin", "gofmt"))
+	os.MkdirAll(filepath.Join(gb.versionsDir, ".tmp-1.18"), os.ModePerm)
+	// a current link to a version that is gone
+	os.MkdirAll(gb.currentDir, os.ModePerm)
+	os.Symlink(filepath.Join(gb.getVersionDir("1.15"), "go", "bin"), gb.currentBinDir)
+
+	diagnostics, err := gb.Doctor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := WriteDiagnosticsJSON(&buf, diagnostics); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []Diagnostic
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	byName := make(map[string]Diagnostic)
+	for _, d := range decoded {
+		byName[d.Name] = d
+	}
+	for name, want := range map[string]Diagnostic{
+		"root":         {Severity: SeverityOK},
+		"current":      {Severity: SeverityError, Fix: "gobrew reset && gobrew use <version>"},
+		"version 1.17": {Severity: SeverityError, Fix: "gobrew install 1.17 --verify-existing"},
+		"leftovers":    {Severity: SeverityWarning, Fix: "gobrew reset"},
+	} {
+		got, ok := byName[name]
+		if !ok {
+			t.Errorf("expected a %s diagnostic in %s", name, buf.String())
+			continue
+		}
+		if got.Severity != want.Severity || got.Fix != want.Fix || got.Message == "" {
+			t.Errorf("%s: got %+v, want %+v", name, got, want)
+		}
+	}
+	if _, ok := byName["version 1.16"]; ok {
+		t.Error("expected no diagnostic for the intact 1.16")
+	}
+}
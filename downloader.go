@@ -0,0 +1,56 @@
+package gobrew
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/kevincobain2000/gobrew/utils"
+)
+
+// downloaderArgs of the external downloaders GOBREW_DOWNLOADER may name,
+// fetching url to dest
+var downloaderArgs = map[string]func(url string, dest string) []string{
+	"aria2c": func(url string, dest string) []string {
+		return []string{"--max-connection-per-server=4", "--split=4", "--allow-overwrite=true",
+			"--dir", filepath.Dir(dest), "--out", filepath.Base(dest), url}
+	},
+	"curl": func(url string, dest string) []string {
+		return []string{"--fail", "--location", "--output", dest, url}
+	},
+}
+
+// download fetches url to dest with the external downloader when one is
+// configured and installed, falling back to the built-in client
+func (gb *GoBrew) download(url string, dest string) error {
+	if gb.offline {
+		return fmt.Errorf("%w: not downloading %s", ErrOfflineMode, url)
+	}
+	if gb.downloader != "" {
+		err := gb.externalDownload(url, dest)
+		if err == nil {
+			return nil
+		}
+		utils.ColorInfo.Fprintf(gb.stdout, "[Info] %s failed, downloading with the built-in client: %s\n", gb.downloader, err)
+		os.Remove(dest)
+	}
+	return utils.Download(url, dest)
+}
+
+func (gb *GoBrew) externalDownload(url string, dest string) error {
+	args, ok := downloaderArgs[gb.downloader]
+	if !ok {
+		return fmt.Errorf("unsupported downloader %q, use aria2c or curl", gb.downloader)
+	}
+	path, err := exec.LookPath(gb.downloader)
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(path, args(url, dest)...)
+	gb.debug.Printf("running %s", strings.Join(cmd.Args, " "))
+	cmd.Stdout = gb.stdout
+	cmd.Stderr = gb.stderr
+	return cmd.Run()
+}
@@ -0,0 +1,80 @@
+//go:build !windows
+// +build !windows
+
+package gobrew
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExternalDownloader(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	fixture := filepath.Join(tempDir(t), "go1.16.tar.gz")
+	writeTarGz(t, fixture, map[string]string{"go/bin/go": "go"})
+	log := filepath.Join(tempDir(t), "curl.log")
+	// the archive is only reachable through the fake curl
+	fakeBin(t, "curl", `echo "$@" > `+log+`
+cp `+fixture+` "$4"
+`)
+	gb.downloader = "curl"
+	gb.registryPath = "http://127.0.0.1:1/"
+
+	if err := gb.mkdirs("1.16"); err != nil {
+		t.Fatal(err)
+	}
+	gb.downloadAndExtract("1.16")
+
+	args, _ := ioutil.ReadFile(log)
+	archive := filepath.Join(gb.downloadsDir, gb.archiveName("1.16"))
+	if want := "--fail --location --output " + archive + " " + gb.archiveURL("1.16") + "\n"; string(args) != want {
+		t.Errorf("got curl %q, want %q", args, want)
+	}
+	if !gb.existsVersion("1.16") {
+		t.Error("expected 1.16 extracted from the archive curl downloaded")
+	}
+}
+
+func TestExternalDownloaderFallback(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	gb.registryPath = serveArchives(t, gb, "1.16").URL + "/"
+	fakeBin(t, "aria2c", "exit 1\n")
+
+	for _, downloader := range []string{"aria2c", "wget", "missing"} {
+		gb.downloader = downloader
+		dest := filepath.Join(tempDir(t), gb.archiveName("1.16"))
+		if err := gb.download(gb.archiveURL("1.16"), dest); err != nil {
+			t.Fatal(err)
+		}
+		if err := gb.extract(dest, tempDir(t), -1); err != nil {
+			t.Errorf("%s: expected the built-in client to download a valid archive: %s", downloader, err)
+		}
+		if downloader == "aria2c" {
+			continue
+		}
+		if err := gb.externalDownload(gb.archiveURL("1.16"), dest); err == nil || !strings.Contains(err.Error(), downloader) {
+			t.Errorf("expected %s rejected, got %v", downloader, err)
+		}
+	}
+}
+
+func TestDownloadHTTPError(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "<html>not found</html>", http.StatusNotFound)
+	}))
+	defer server.Close()
+	dest := filepath.Join(tempDir(t), "go1.16.tar.gz")
+
+	if err := gb.download(server.URL+"/go1.16.tar.gz", dest); err == nil {
+		t.Error("expected an error for a 404")
+	}
+	if _, err := os.Stat(dest); !os.IsNotExist(err) {
+		t.Errorf("expected no file left for a 404, got %v", err)
+	}
+}
@@ -0,0 +1,195 @@
+package gobrew
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// envDir holds <version>.env files applied while that version is active
+const envDir = "env"
+
+// envFile of version
+func (gb *GoBrew) envFile(version string) string {
+	return filepath.Join(gb.installDir, envDir, version+".env")
+}
+
+// VersionEnv returns the KEY=VALUE pairs of the env file of version, e.g.
+// GOFLAGS or GOEXPERIMENT, nothing when it has none
+func (gb *GoBrew) VersionEnv(version string) ([]string, error) {
+	return parseEnvFile(gb.envFile(version))
+}
+
+// parseEnvFile reads simple KEY=VALUE lines, skipping blanks and # comments
+func parseEnvFile(path string) ([]string, error) {
+	env := make([]string, 0)
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return env, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		i := strings.Index(line, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("%s:%d: expected KEY=VALUE", path, n)
+		}
+		key := strings.TrimSpace(line[:i])
+		value := strings.Trim(strings.TrimSpace(line[i+1:]), `"'`)
+		env = append(env, key+"="+value)
+	}
+	return env, scanner.Err()
+}
+
+// mergeEnv returns base with every KEY=VALUE of overrides replacing or
+// appending to it
+func mergeEnv(base []string, overrides ...string) []string {
+	merged := make([]string, 0, len(base)+len(overrides))
+	index := make(map[string]int)
+	for _, kv := range append(append([]string{}, base...), overrides...) {
+		key := kv
+		if i := strings.Index(kv, "="); i >= 0 {
+			key = kv[:i]
+		}
+		if i, ok := index[key]; ok {
+			merged[i] = kv
+			continue
+		}
+		index[key] = len(merged)
+		merged = append(merged, kv)
+	}
+	return merged
+}
+
+// ExecEnv returns the environment a command runs in under version: its go
+// first on PATH, its GOROOT, GOTOOLCHAIN and its env file applied
+func (gb *GoBrew) ExecEnv(version string) ([]string, error) {
+	versionEnv, err := gb.VersionEnv(version)
+	if err != nil {
+		return nil, err
+	}
+	goroot := gb.versionGoroot(version)
+	env := mergeEnv(os.Environ(),
+		"GOROOT="+goroot,
+		"PATH="+gb.versionBinDir(version)+string(os.PathListSeparator)+os.Getenv("PATH"))
+	env = mergeEnv(env, gb.toolchainEnv()...)
+	return mergeEnv(env, versionEnv...), nil
+}
+
+// Exec runs args with version active for that command only, leaving the
+// current version untouched
+func (gb *GoBrew) Exec(version string, args []string) error {
+	version = gb.versionName(version)
+	if len(args) == 0 {
+		return fmt.Errorf("no command provided")
+	}
+	if !gb.existsVersion(version) {
+		return fmt.Errorf("version %s is not installed", version)
+	}
+	env, err := gb.ExecEnv(version)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	// look the command up on the PATH of the version, so `go` is its go
+	for _, kv := range env {
+		if strings.HasPrefix(kv, "PATH=") {
+			if path, err := lookPathIn(args[0], strings.TrimPrefix(kv, "PATH=")); err == nil {
+				cmd.Path = path
+			}
+		}
+	}
+	cmd.Env = env
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = gb.stdout
+	cmd.Stderr = gb.stderr
+	return cmd.Run()
+}
+
+// lookPathIn finds the executable name in the directories of path
+func lookPathIn(name string, path string) (string, error) {
+	if strings.Contains(name, string(os.PathSeparator)) {
+		return name, nil
+	}
+	for _, dir := range filepath.SplitList(path) {
+		for _, candidate := range []string{name, exeName(name)} {
+			full := filepath.Join(dir, candidate)
+			if fi, err := os.Stat(full); err == nil && !fi.IsDir() && fi.Mode()&0111 != 0 {
+				return full, nil
+			}
+		}
+	}
+	return "", exec.ErrNotFound
+}
+
+// ShellEnv returns the export lines that put the current version on PATH, set
+// GOTOOLCHAIN and apply its env file, for `eval "$(gobrew shellenv)"`
+func (gb *GoBrew) ShellEnv() (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "export PATH=%q\n", gb.currentBinDir+string(os.PathListSeparator)+"$PATH")
+	if gb.goToolchain != "" {
+		fmt.Fprintf(&b, "export GOTOOLCHAIN=%q\n", gb.goToolchain)
+	}
+
+	if cv := gb.CurrentVersion(); cv != "" {
+		versionEnv, err := gb.VersionEnv(cv)
+		if err != nil {
+			return "", err
+		}
+		for _, kv := range versionEnv {
+			i := strings.Index(kv, "=")
+			fmt.Fprintf(&b, "export %s=%q\n", kv[:i], kv[i+1:])
+		}
+	}
+	return b.String(), nil
+}
+
+// toolchainEnv pins GOTOOLCHAIN so go 1.21+ runs the toolchain gobrew
+// selected instead of downloading the one go.mod asks for, see GOBREW_GOTOOLCHAIN
+func (gb *GoBrew) toolchainEnv() []string {
+	if gb.goToolchain == "" {
+		return nil
+	}
+	return []string{"GOTOOLCHAIN=" + gb.goToolchain}
+}
+
+// Goroot returns the GOROOT of the current version
+func (gb *GoBrew) Goroot() (string, error) {
+	cv := gb.CurrentVersion()
+	if cv == "" {
+		return "", fmt.Errorf("no current version, use one first")
+	}
+	return gb.versionGoroot(cv), nil
+}
+
+// Which returns the go binary of the current version
+func (gb *GoBrew) Which() (string, error) {
+	goroot, err := gb.Goroot()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(goroot, filepath.Base(filepath.FromSlash(gb.binSubpath)), exeName("go")), nil
+}
+
+// Vars returns GO= and GOROOT= lines of the current version without export,
+// e.g. for a Makefile to include
+func (gb *GoBrew) Vars() (string, error) {
+	goBin, err := gb.Which()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("GO=%s\nGOROOT=%s\n", goBin, filepath.Dir(filepath.Dir(goBin))), nil
+}
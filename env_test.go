@@ -0,0 +1,134 @@
+package gobrew
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kevincobain2000/gobrew/utils"
+)
+
+// writeEnvFile writes the env file of version
+func writeEnvFile(t *testing.T, gb GoBrew, version string, content string) {
+	t.Helper()
+	os.MkdirAll(filepath.Join(gb.installDir, envDir), os.ModePerm)
+	if err := ioutil.WriteFile(gb.envFile(version), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVersionEnv(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	writeEnvFile(t, gb, "1.17", "# flags for 1.17\nGOFLAGS=-mod=mod\n\nexport GOEXPERIMENT=\"unified\"\n")
+
+	env, err := gb.VersionEnv("1.17")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(env, []string{"GOFLAGS=-mod=mod", "GOEXPERIMENT=unified"}) {
+		t.Errorf("unexpected env %v", env)
+	}
+	if env, _ := gb.VersionEnv("1.16"); len(env) != 0 {
+		t.Errorf("expected no env for 1.16, got %v", env)
+	}
+}
+
+func TestExecAppliesVersionEnv(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	installFakeVersion(t, gb, "1.16", "go version go1.16 linux/amd64")
+	installFakeVersion(t, gb, "1.17", "go version go1.17 linux/amd64")
+	writeEnvFile(t, gb, "1.17", "GOFLAGS=-mod=mod\n")
+	os.Setenv("GOFLAGS", "-mod=vendor")
+	defer os.Unsetenv("GOFLAGS")
+
+	for version, want := range map[string]string{
+		"1.17": "go version go1.17 linux/amd64\n-mod=mod\n",
+		"1.16": "go version go1.16 linux/amd64\n-mod=vendor\n",
+	} {
+		var buf bytes.Buffer
+		gb.stdout = &buf
+		if err := gb.Exec(version, []string{"sh", "-c", "go version; echo $GOFLAGS"}); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != want {
+			t.Errorf("%s: got %q, want %q", version, buf.String(), want)
+		}
+	}
+}
+
+func TestShellEnv(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	installFakeVersion(t, gb, "1.17", "go version go1.17 linux/amd64")
+	useFixture(t, gb, "1.17")
+	writeEnvFile(t, gb, "1.17", "GOEXPERIMENT=unified\n")
+
+	out, err := gb.ShellEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "export GOEXPERIMENT=\"unified\"\n") || !strings.Contains(out, gb.currentBinDir) {
+		t.Errorf("unexpected shellenv %q", out)
+	}
+}
+
+func TestVars(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	if _, err := gb.Vars(); err == nil {
+		t.Error("expected an error without a current version")
+	}
+	installFakeVersion(t, gb, "1.17", "go version go1.17 linux/amd64")
+	useFixture(t, gb, "1.17")
+	goroot := filepath.Join(gb.versionsDir, "1.17", "go")
+
+	out, err := gb.Vars()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "GO=" + goroot + "/bin/go\nGOROOT=" + goroot + "\n"; out != want {
+		t.Errorf("got %q, want %q", out, want)
+	}
+	if goBin, err := gb.Which(); err != nil || goBin != goroot+"/bin/go" {
+		t.Errorf("which: got %q, %v", goBin, err)
+	}
+	if got, err := gb.Goroot(); err != nil || got != goroot {
+		t.Errorf("goroot: got %q, %v", got, err)
+	}
+}
+
+func TestGoToolchain(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	installFakeVersion(t, gb, "1.21.3", "go version go1.21.3 linux/amd64")
+	useFixture(t, gb, "1.21.3")
+
+	out, err := gb.ShellEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "export GOTOOLCHAIN=\"local\"\n") {
+		t.Errorf("expected GOTOOLCHAIN=local exported, got %q", out)
+	}
+	env, err := gb.ExecEnv("1.21.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !utils.Find(env, "GOTOOLCHAIN=local") {
+		t.Error("expected GOTOOLCHAIN=local in the exec environment")
+	}
+
+	// an env file still wins
+	writeEnvFile(t, gb, "1.21.3", "GOTOOLCHAIN=go1.22.0\n")
+	env, _ = gb.ExecEnv("1.21.3")
+	if !utils.Find(env, "GOTOOLCHAIN=go1.22.0") || utils.Find(env, "GOTOOLCHAIN=local") {
+		t.Error("expected the env file to override GOTOOLCHAIN")
+	}
+
+	gb.goToolchain = ""
+	out, _ = gb.ShellEnv()
+	if strings.Contains(out, "GOTOOLCHAIN=\"local\"") {
+		t.Errorf("expected GOTOOLCHAIN left alone when disabled, got %q", out)
+	}
+}
@@ -0,0 +1,91 @@
+package gobrew
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kevincobain2000/gobrew/utils"
+)
+
+const defaultDownloadMaxAge = 24 * time.Hour
+
+// EnableKeepDownloads makes Install leave its archive in the downloads dir,
+// where a reinstall finds it, instead of removing it. Leftovers aren't evicted
+// by age either, GOBREW_DOWNLOAD_CACHE_MAX still bounds the dir
+func (gb *GoBrew) EnableKeepDownloads() {
+	gb.keepDownloads = true
+}
+
+// EvictDownloads removes files older than maxAge from the downloads dir, left
+// behind by aborted installs. A shared GOBREW_DOWNLOAD_DIR only has our
+// archives and partial downloads removed
+func (gb *GoBrew) EvictDownloads(maxAge time.Duration) error {
+	entries, err := ioutil.ReadDir(gb.downloadsDir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	shared := gb.downloadsDir != filepath.Join(gb.installDir, "downloads")
+	cutoff := time.Now().Add(-maxAge)
+	for _, entry := range entries {
+		if entry.IsDir() || !entry.ModTime().Before(cutoff) {
+			continue
+		}
+		if shared && !isDownload(entry.Name()) {
+			continue
+		}
+		gb.debug.Printf("evicting %s from %s", entry.Name(), entry.ModTime())
+		if err := os.Remove(filepath.Join(gb.downloadsDir, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TrimDownloadCache evicts the oldest downloads until the downloads dir takes
+// at most max bytes
+func (gb *GoBrew) TrimDownloadCache(max int64) error {
+	entries, err := ioutil.ReadDir(gb.downloadsDir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	shared := gb.downloadsDir != filepath.Join(gb.installDir, "downloads")
+	var total int64
+	files := entries[:0]
+	for _, entry := range entries {
+		if entry.IsDir() || (shared && !isDownload(entry.Name())) {
+			continue
+		}
+		total += entry.Size()
+		files = append(files, entry)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].ModTime().Before(files[j].ModTime())
+	})
+	for _, file := range files {
+		if total <= max {
+			break
+		}
+		gb.debug.Printf("evicting %s, downloads take %s over %s", file.Name(), utils.HumanSize(total), utils.HumanSize(max))
+		if err := os.Remove(filepath.Join(gb.downloadsDir, file.Name())); err != nil {
+			return err
+		}
+		total -= file.Size()
+	}
+	return nil
+}
+
+// isDownload reports whether name is an archive, complete or partial, gobrew downloads
+func isDownload(name string) bool {
+	name = strings.TrimSuffix(name, ".part")
+	return strings.HasPrefix(name, "go") && strings.HasSuffix(name, ".tar.gz")
+}
@@ -0,0 +1,94 @@
+package gobrew
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEvictDownloads(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	os.MkdirAll(gb.downloadsDir, os.ModePerm)
+	old := time.Now().Add(-48 * time.Hour)
+	for name, mtime := range map[string]time.Time{
+		"go1.16.linux-amd64.tar.gz.part": old,
+		"go1.15.linux-amd64.tar.gz":      old,
+		"go1.17.linux-amd64.tar.gz":      time.Now(),
+	} {
+		path := filepath.Join(gb.downloadsDir, name)
+		ioutil.WriteFile(path, []byte("partial"), 0644)
+		os.Chtimes(path, mtime, mtime)
+	}
+
+	if err := gb.EvictDownloads(24 * time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	left, _ := filepath.Glob(filepath.Join(gb.downloadsDir, "*"))
+	if len(left) != 1 || filepath.Base(left[0]) != "go1.17.linux-amd64.tar.gz" {
+		t.Errorf("expected only the recent archive left, got %v", left)
+	}
+}
+
+func TestEvictSharedDownloads(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	gb.downloadsDir = tempDir(t)
+	old := time.Now().Add(-48 * time.Hour)
+	for _, name := range []string{"go1.16.linux-amd64.tar.gz.part", "unrelated.tar.gz"} {
+		path := filepath.Join(gb.downloadsDir, name)
+		ioutil.WriteFile(path, nil, 0644)
+		os.Chtimes(path, old, old)
+	}
+
+	if err := gb.EvictDownloads(time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	left, _ := filepath.Glob(filepath.Join(gb.downloadsDir, "*"))
+	if len(left) != 1 || filepath.Base(left[0]) != "unrelated.tar.gz" {
+		t.Errorf("expected other files in a shared download dir kept, got %v", left)
+	}
+}
+
+func TestTrimDownloadCache(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	os.MkdirAll(gb.downloadsDir, os.ModePerm)
+	for i, name := range []string{"go1.15.tar.gz", "go1.16.tar.gz", "go1.17.tar.gz", "go1.18.tar.gz"} {
+		path := filepath.Join(gb.downloadsDir, name)
+		ioutil.WriteFile(path, make([]byte, 100), 0644)
+		mtime := time.Now().Add(time.Duration(i-10) * time.Hour)
+		os.Chtimes(path, mtime, mtime)
+	}
+
+	if err := gb.TrimDownloadCache(250); err != nil {
+		t.Fatal(err)
+	}
+	left, _ := filepath.Glob(filepath.Join(gb.downloadsDir, "*"))
+	if len(left) != 2 || filepath.Base(left[0]) != "go1.17.tar.gz" || filepath.Base(left[1]) != "go1.18.tar.gz" {
+		t.Errorf("expected the 2 newest archives kept, got %v", left)
+	}
+
+	if err := gb.TrimDownloadCache(1000); err != nil {
+		t.Fatal(err)
+	}
+	if left, _ := filepath.Glob(filepath.Join(gb.downloadsDir, "*")); len(left) != 2 {
+		t.Errorf("expected nothing evicted under the cap, got %v", left)
+	}
+}
+
+func TestInstallKeepDownloads(t *testing.T) {
+	for _, keep := range []bool{false, true} {
+		gb := newTestGoBrew(tempDir(t))
+		gb.registryPath = serveArchives(t, gb, "1.16").URL + "/"
+		if keep {
+			gb.EnableKeepDownloads()
+		}
+
+		gb.Install("1.16")
+
+		_, err := os.Stat(filepath.Join(gb.downloadsDir, gb.archiveName("1.16")))
+		if kept := err == nil; kept != keep {
+			t.Errorf("keepDownloads %v: archive kept %v", keep, kept)
+		}
+	}
+}
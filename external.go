@@ -0,0 +1,87 @@
+package gobrew
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kevincobain2000/gobrew/utils"
+)
+
+const externalPrefix = "external-"
+
+// isExternalVersion tells whether version names a GOROOT registered with
+// UseExternal
+func isExternalVersion(version string) bool {
+	return strings.HasPrefix(version, externalPrefix)
+}
+
+// UseExternal switches to a GOROOT outside of gobrew, e.g. Go built from
+// source. It is registered as versionsDir/external-<name>/go, a symlink to
+// goroot, so it is listed and used like any installed version
+func (gb *GoBrew) UseExternal(goroot string) error {
+	goroot, err := filepath.Abs(goroot)
+	if err != nil {
+		return err
+	}
+	return gb.useExternal(goroot, externalName(goroot))
+}
+
+// UseExternalCommit is UseExternal registering goroot as the build of commit
+// hash, named commit-<hash> instead of after its directory
+func (gb *GoBrew) UseExternalCommit(goroot string, hash string) error {
+	version, err := CommitVersion(hash)
+	if err != nil {
+		return err
+	}
+	goroot, err = filepath.Abs(goroot)
+	if err != nil {
+		return err
+	}
+	return gb.useExternal(goroot, version)
+}
+
+// useExternal registers the absolute goroot as version and makes it current
+func (gb *GoBrew) useExternal(goroot string, version string) error {
+	if _, err := os.Stat(filepath.Join(goroot, "bin", exeName("go"))); err != nil {
+		return fmt.Errorf("%s is not a GOROOT: %w", goroot, err)
+	}
+
+	link := filepath.Join(gb.getVersionDir(version), "go")
+	if target, err := os.Readlink(link); err == nil {
+		if target != goroot {
+			return fmt.Errorf("version %s already points to %s", version, target)
+		}
+	} else {
+		if gb.existsVersion(version) {
+			return fmt.Errorf("version %s is already installed", version)
+		}
+		if err := os.MkdirAll(gb.getVersionDir(version), os.ModePerm); err != nil {
+			return err
+		}
+		if err := os.Symlink(goroot, link); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(gb.currentDir, os.ModePerm); err != nil {
+		return err
+	}
+
+	utils.ColorInfo.Fprintf(gb.stdout, "[Info] Changing go version to: %s (%s)\n", version, goroot)
+	if err := gb.switchCurrent(version); err != nil {
+		return err
+	}
+	utils.ColorSuccess.Fprintf(gb.stdout, "[Success] Changed go version to: %s\n", version)
+	return nil
+}
+
+// externalName derives the version name of goroot from its directory, or
+// from its parent for the usual .../go checkout
+func externalName(goroot string) string {
+	name := filepath.Base(goroot)
+	if name == "go" {
+		name = filepath.Base(filepath.Dir(goroot))
+	}
+	return externalPrefix + name
+}
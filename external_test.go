@@ -0,0 +1,77 @@
+package gobrew
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestUseExternal(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	goroot := filepath.Join(tempDir(t), "gosrc", "go")
+	os.MkdirAll(filepath.Join(goroot, "bin"), os.ModePerm)
+	if err := ioutil.WriteFile(filepath.Join(goroot, "bin", "go"), []byte("#!/bin/sh\necho go version devel\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := gb.UseExternal(goroot); err != nil {
+		t.Fatal(err)
+	}
+
+	if cv := gb.CurrentVersion(); cv != "external-gosrc" {
+		t.Errorf("expected external-gosrc to be current, got %q", cv)
+	}
+	for link, want := range map[string]string{
+		gb.currentBinDir: filepath.Join(goroot, "bin"),
+		gb.currentGoDir:  goroot,
+	} {
+		got, err := filepath.EvalSymlinks(link)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _ = filepath.EvalSymlinks(want)
+		if got != want {
+			t.Errorf("expected %s to resolve to %s, got %s", link, want, got)
+		}
+	}
+	installed, _ := gb.InstalledVersions()
+	if len(installed) != 1 || installed[0] != "external-gosrc" {
+		t.Errorf("expected the external version to be listed, got %v", installed)
+	}
+
+	// using it again is fine, another GOROOT with the same name is not
+	if err := gb.UseExternal(goroot); err != nil {
+		t.Errorf("expected re-using the same GOROOT to succeed, got %v", err)
+	}
+	other := filepath.Join(tempDir(t), "gosrc")
+	os.MkdirAll(filepath.Join(other, "bin"), os.ModePerm)
+	ioutil.WriteFile(filepath.Join(other, "bin", "go"), nil, 0755)
+	if err := gb.UseExternal(other); err == nil {
+		t.Error("expected an error registering a different GOROOT under the same name")
+	}
+
+	if err := gb.UseExternal(tempDir(t)); err == nil {
+		t.Error("expected an error for a directory without bin/go")
+	}
+}
+
+func TestUseExternalListed(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	installFakeVersion(t, gb, "1.17", "go version go1.17 linux/amd64")
+	goroot := filepath.Join(tempDir(t), "mytip", "go")
+	os.MkdirAll(filepath.Join(goroot, "bin"), os.ModePerm)
+	ioutil.WriteFile(filepath.Join(goroot, "bin", "go"), []byte("#!/bin/sh\necho go version devel\n"), 0755)
+
+	if err := gb.UseExternal(goroot); err != nil {
+		t.Fatal(err)
+	}
+	installed, err := gb.InstalledVersions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(installed, []string{"1.17", "external-mytip"}) {
+		t.Errorf("expected external-mytip to be listed after 1.17, got %v", installed)
+	}
+}
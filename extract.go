@@ -0,0 +1,493 @@
+package gobrew
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/kevincobain2000/gobrew/utils"
+	"github.com/ulikunitz/xz"
+)
+
+// extractVersion extracts archive into a temporary directory in scratchDir
+// and renames it into place once complete, so a failed extraction never
+// leaves a version that looks installed
+func (gb *GoBrew) extractVersion(archive string, version string) error {
+	return gb.extractVerified(archive, version, "")
+}
+
+// extractVerified is extractVersion checking archive hashes to the sha256
+// expected, in the same read of archive as the extraction rather than a
+// pass of its own. A mismatch is reported over any extraction error, as a
+// corrupt archive may well fail to decompress. An empty expected is not checked
+func (gb *GoBrew) extractVerified(archive string, version string, expected string) error {
+	tmp := gb.extractTmpDir(version)
+	os.RemoveAll(tmp)
+	var h hash.Hash
+	if expected != "" {
+		h = sha256.New()
+	}
+	// the entries are written before the checksum is known, which is only
+	// safe as they land in tmp, removed on a mismatch and renamed into
+	// place once verified
+	err := gb.extractHashed(archive, tmp, gb.stripComponents, h)
+	if h != nil {
+		if mismatch := matchChecksum(archive, hex.EncodeToString(h.Sum(nil)), expected); mismatch != nil {
+			err = mismatch
+		}
+	}
+	if err != nil {
+		os.RemoveAll(tmp)
+		return err
+	}
+	if err := writeManifest(tmp); err != nil {
+		os.RemoveAll(tmp)
+		return err
+	}
+
+	versionDir := gb.getVersionDir(version)
+	// mkdirs leaves an empty version directory behind, which can't be renamed over
+	os.Remove(versionDir)
+	if err := os.Rename(tmp, versionDir); err != nil {
+		os.RemoveAll(tmp)
+		return err
+	}
+	return nil
+}
+
+// extractTmpDir that version is extracted into before the rename into place
+func (gb *GoBrew) extractTmpDir(version string) string {
+	return filepath.Join(gb.scratchDir(), ".tmp-"+version)
+}
+
+// scratchDir is GOBREW_TMPDIR when set and on the same filesystem as
+// versionsDir, so the final rename stays atomic, otherwise versionsDir
+func (gb *GoBrew) scratchDir() string {
+	if gb.tmpDir == "" {
+		return gb.versionsDir
+	}
+	if err := checkSameFilesystem(gb.tmpDir, gb.versionsDir); err != nil {
+		utils.ColorInfo.Fprintf(gb.stdout, "[Info] Not extracting in GOBREW_TMPDIR=%s, using %s: %s\n", gb.tmpDir, gb.versionsDir, err)
+		return gb.versionsDir
+	}
+	return gb.tmpDir
+}
+
+// checkSameFilesystem renames a probe file from dir to target, which only
+// succeeds within a filesystem
+func checkSameFilesystem(dir string, target string) error {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	probe, err := ioutil.TempFile(dir, ".gobrew-probe-")
+	if err != nil {
+		return err
+	}
+	probe.Close()
+	defer os.Remove(probe.Name())
+
+	moved := filepath.Join(target, filepath.Base(probe.Name()))
+	if err := os.Rename(probe.Name(), moved); err != nil {
+		return fmt.Errorf("not on the same filesystem as %s", target)
+	}
+	return os.Remove(moved)
+}
+
+// extract unpacks the tarball archive, see decompress, so the toolchain always lands in
+// dest/go, whatever directory the archive nests it under. stripComponents
+// leading path elements are dropped from every entry; a negative value detects
+// them with depthTracker while extracting.
+//
+// Every entry is written in place, so archive and dest may sit on different
+// filesystems (e.g. when GOBREW_ROOT points elsewhere): nothing is ever renamed
+// across devices.
+func (gb *GoBrew) extract(archive string, dest string, stripComponents int) error {
+	return gb.extractHashed(archive, dest, stripComponents, nil)
+}
+
+// extractHashed is extract writing every byte read from archive to h as well,
+// when h is not nil, even when the extraction fails
+func (gb *GoBrew) extractHashed(archive string, dest string, stripComponents int, h io.Writer) error {
+	root := filepath.Join(dest, "go")
+	if err := os.MkdirAll(root, os.ModePerm); err != nil {
+		return err
+	}
+
+	// a negative stripComponents is taken from the first entries, the
+	// directories every release nests the toolchain under, and checked
+	// against the whole archive once walked
+	detect := stripComponents < 0
+	var tracker depthTracker
+	pool := newWritePool(gb.extractWorkers)
+	files := 0
+	err := walkTarHashed(archive, h, func(hdr *tar.Header, r io.Reader) error {
+		if detect {
+			tracker.add(hdr)
+			if !tracker.started {
+				// no path elements, e.g. ./
+				return nil
+			}
+			if stripComponents < 0 {
+				stripComponents = tracker.depth()
+			}
+		}
+		name := stripPath(hdr.Name, stripComponents)
+		if name == "" || (gb.minimal && minimalSkips(name)) {
+			return nil
+		}
+		target := filepath.Join(root, filepath.FromSlash(name))
+		if !strings.HasPrefix(target, root+string(os.PathSeparator)) {
+			return fmt.Errorf("archive entry %s escapes %s", hdr.Name, root)
+		}
+		if hdr.Typeflag == tar.TypeSymlink {
+			if err := checkLinkname(root, target, hdr.Linkname); err != nil {
+				return fmt.Errorf("archive entry %s: %w", hdr.Name, err)
+			}
+		}
+		if gb.report != nil && isRegular(hdr) {
+			files++
+			gb.report(ProgressEvent{Stage: StageExtracting, Files: files})
+		}
+		if pool != nil && isRegular(hdr) {
+			return pool.write(target, r, hdr.FileInfo().Mode().Perm(), tarXattrs(hdr))
+		}
+		return writeEntry(hdr, r, target)
+	})
+	if pool != nil {
+		if poolErr := pool.wait(); err == nil {
+			err = poolErr
+		}
+	}
+	if err == nil && detect && tracker.depth() != stripComponents {
+		// the first entries misled, e.g. a toolchain nested next to other
+		// files, so it is extracted again at the depth of the whole archive.
+		// h already got every byte of the archive
+		gb.debug.Printf("extracting %s again, its toolchain is %d deep rather than %d", archive, tracker.depth(), stripComponents)
+		if err := os.RemoveAll(root); err != nil {
+			return err
+		}
+		return gb.extractHashed(archive, dest, tracker.depth(), nil)
+	}
+	return err
+}
+
+// minimalDirs of the toolchain are not extracted when GOBREW_MINIMAL=1
+var minimalDirs = []string{"src", "test", "api", "doc"}
+
+// minimalSkips reports whether the toolchain relative name sits in minimalDirs
+func minimalSkips(name string) bool {
+	top := strings.SplitN(name, "/", 2)[0]
+	for _, dir := range minimalDirs {
+		if top == dir {
+			return true
+		}
+	}
+	return false
+}
+
+func isRegular(hdr *tar.Header) bool {
+	return hdr.Typeflag == tar.TypeReg || hdr.Typeflag == tar.TypeRegA
+}
+
+// writePool writes regular files concurrently. Parent directories are created
+// by the caller before a file is queued, so directories are still made in
+// archive order; symlinks and directories never go through the pool.
+type writePool struct {
+	jobs chan fileJob
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	err  error
+}
+
+type fileJob struct {
+	target string
+	data   []byte
+	mode   os.FileMode
+	xattrs map[string]string
+}
+
+// newWritePool starts workers writers, nil when extraction should stay sequential
+func newWritePool(workers int) *writePool {
+	if workers <= 1 {
+		return nil
+	}
+	p := &writePool{jobs: make(chan fileJob, workers)}
+	for i := 0; i < workers; i++ {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			for job := range p.jobs {
+				err := writeFile(job.target, bytes.NewReader(job.data), job.mode)
+				if err == nil {
+					err = setXattrs(job.target, job.xattrs)
+				}
+				if err != nil {
+					p.fail(err)
+				}
+			}
+		}()
+	}
+	return p
+}
+
+// write buffers the entry, the tar stream can't be shared between goroutines
+func (p *writePool) write(target string, r io.Reader, mode os.FileMode, xattrs map[string]string) error {
+	if err := p.failed(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+		return err
+	}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	p.jobs <- fileJob{target: target, data: data, mode: mode, xattrs: xattrs}
+	return nil
+}
+
+// wait for the queued files and return the first write error
+func (p *writePool) wait() error {
+	close(p.jobs)
+	p.wg.Wait()
+	return p.failed()
+}
+
+func (p *writePool) fail(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err == nil {
+		p.err = err
+	}
+}
+
+func (p *writePool) failed() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+// checkLinkname rejects a symlink at target that points outside root
+func checkLinkname(root string, target string, linkname string) error {
+	if linkname == "" || path.IsAbs(linkname) || filepath.IsAbs(linkname) {
+		return fmt.Errorf("symlink to %q is not relative", linkname)
+	}
+	resolved := filepath.Join(filepath.Dir(target), filepath.FromSlash(linkname))
+	if resolved != root && !strings.HasPrefix(resolved, root+string(os.PathSeparator)) {
+		return fmt.Errorf("symlink to %s escapes %s", linkname, root)
+	}
+	return nil
+}
+
+// writeEntry creates target from a single tar entry
+func writeEntry(hdr *tar.Header, r io.Reader, target string) error {
+	mode := hdr.FileInfo().Mode().Perm()
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		return os.MkdirAll(target, mode|0700)
+	case tar.TypeReg, tar.TypeRegA:
+		if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+			return err
+		}
+		if err := writeFile(target, r, mode); err != nil {
+			return err
+		}
+		return setXattrs(target, tarXattrs(hdr))
+	case tar.TypeSymlink:
+		if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+			return err
+		}
+		os.Remove(target)
+		return os.Symlink(hdr.Linkname, target)
+	}
+	// hard links, devices and the like never appear in a go release
+	return nil
+}
+
+// paxXattrPrefix marks the PAX records holding extended attributes
+const paxXattrPrefix = "SCHILY.xattr."
+
+// tarXattrs returns the extended attributes recorded for an entry, by name
+func tarXattrs(hdr *tar.Header) map[string]string {
+	var xattrs map[string]string
+	for key, value := range hdr.PAXRecords {
+		if !strings.HasPrefix(key, paxXattrPrefix) {
+			continue
+		}
+		if xattrs == nil {
+			xattrs = make(map[string]string)
+		}
+		xattrs[strings.TrimPrefix(key, paxXattrPrefix)] = value
+	}
+	return xattrs
+}
+
+func writeFile(target string, r io.Reader, mode os.FileMode) error {
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// walkTar calls fn for every entry of the tarball archive
+func walkTar(archive string, fn func(hdr *tar.Header, r io.Reader) error) error {
+	return walkTarHashed(archive, nil, fn)
+}
+
+// openArchive is swapped out by tests
+var openArchive = os.Open
+
+// walkTarHashed is walkTar copying archive to h as it is read. Whatever the
+// walk leaves unread is copied on return, so h always gets the whole archive
+func walkTarHashed(archive string, h io.Writer, fn func(hdr *tar.Header, r io.Reader) error) error {
+	f, err := openArchive(archive)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var raw io.Reader = f
+	if h != nil {
+		raw = io.TeeReader(f, h)
+		defer io.Copy(ioutil.Discard, raw)
+	}
+	r, err := decompress(raw)
+	if err != nil {
+		return fmt.Errorf("%s: %w", archive, err)
+	}
+	defer r.Close()
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(hdr, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// Magic bytes of the compressions decompress detects
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	xzMagic    = []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}
+	bzip2Magic = []byte("BZh")
+)
+
+// decompress detects the compression of r from its magic bytes. Official
+// releases are gzipped, mirrors may repackage them as xz or bzip2 whatever
+// the file is named
+func decompress(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(len(xzMagic))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		return gzip.NewReader(br)
+	case bytes.HasPrefix(magic, xzMagic):
+		xr, err := xz.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		return ioutil.NopCloser(xr), nil
+	case bytes.HasPrefix(magic, bzip2Magic):
+		return ioutil.NopCloser(bzip2.NewReader(br)), nil
+	}
+	return nil, fmt.Errorf("unsupported compression, expected gzip, xz or bzip2")
+}
+
+// depthTracker works out how many leading path elements sit above the
+// toolchain root, i.e. the directory holding bin/go, from the entries of an
+// archive given one by one. Archives without a go binary fall back to their
+// shared top-level directories
+type depthTracker struct {
+	// binDepth of the shallowest bin/go, when seen is set
+	binDepth int
+	seen     bool
+	// common directories shared by every entry, once started
+	common  []string
+	started bool
+}
+
+func (d *depthTracker) add(hdr *tar.Header) {
+	parts := splitPath(hdr.Name)
+	if len(parts) == 0 {
+		return
+	}
+
+	n := len(parts)
+	if hdr.Typeflag != tar.TypeDir && n >= 2 && parts[n-2] == "bin" && (parts[n-1] == "go" || parts[n-1] == "go.exe") {
+		if !d.seen || n-2 < d.binDepth {
+			d.binDepth = n - 2
+			d.seen = true
+		}
+	}
+
+	// directories shared by every entry
+	dirs := parts
+	if hdr.Typeflag != tar.TypeDir {
+		dirs = parts[:n-1]
+	}
+	if !d.started {
+		d.common = dirs
+		d.started = true
+		return
+	}
+	i := 0
+	for i < len(d.common) && i < len(dirs) && d.common[i] == dirs[i] {
+		i++
+	}
+	d.common = d.common[:i]
+}
+
+// depth of the toolchain from the entries added so far
+func (d *depthTracker) depth() int {
+	if d.seen {
+		return d.binDepth
+	}
+	return len(d.common)
+}
+
+// stripPath drops the first n elements of a slash separated archive path
+func stripPath(name string, n int) string {
+	parts := splitPath(name)
+	if len(parts) <= n {
+		return ""
+	}
+	return strings.Join(parts[n:], "/")
+}
+
+func splitPath(name string) []string {
+	parts := make([]string, 0)
+	for _, part := range strings.Split(name, "/") {
+		if part != "" && part != "." {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
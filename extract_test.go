@@ -0,0 +1,500 @@
+package gobrew
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ulikunitz/xz"
+)
+
+func TestExtractAcrossRoots(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	gb.downloadsDir = filepath.Join(tempDir(t), "downloads")
+
+	archive := filepath.Join(gb.downloadsDir, "go1.16.linux-amd64.tar.gz")
+	writeTarGz(t, archive, map[string]string{"go/bin/go": "#!/bin/sh\n"})
+
+	if err := gb.extract(archive, gb.getVersionDir("1.16"), -1); err != nil {
+		t.Fatalf("extract: %s", err)
+	}
+	if !gb.existsVersion("1.16") {
+		t.Errorf("expected version 1.16 to exist under %s", gb.versionsDir)
+	}
+	if _, err := os.Stat(archive); err != nil {
+		t.Errorf("archive should be left in place: %s", err)
+	}
+}
+
+func TestExtractNestedArchives(t *testing.T) {
+	tests := []struct {
+		name  string
+		strip int
+		files map[string]string
+	}{
+		{name: "official layout", strip: -1, files: map[string]string{"go/bin/go": "go", "go/VERSION": "go1.16"}},
+		{name: "flat", strip: -1, files: map[string]string{"bin/go": "go", "VERSION": "go1.16"}},
+		{name: "nested twice", strip: -1, files: map[string]string{"repack/go/bin/go": "go", "repack/go/VERSION": "go1.16", "repack/README": ""}},
+		{name: "windows binary", strip: -1, files: map[string]string{"dist/go/bin/go.exe": "go", "dist/go/VERSION": "go1.16"}},
+		{name: "explicit strip", strip: 3, files: map[string]string{"a/b/c/bin/go": "go", "a/b/c/VERSION": "go1.16"}},
+		{name: "no binary", strip: -1, files: map[string]string{"x/go/VERSION": "go1.16", "x/go/bin/gofmt": "gofmt"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gb := newTestGoBrew(tempDir(t))
+			archive := filepath.Join(gb.downloadsDir, "go.tar.gz")
+			writeTarGz(t, archive, tt.files)
+
+			if err := gb.extract(archive, gb.getVersionDir("1.16"), tt.strip); err != nil {
+				t.Fatalf("extract: %s", err)
+			}
+			if _, err := os.Stat(filepath.Join(gb.getVersionDir("1.16"), "go", "VERSION")); err != nil {
+				t.Errorf("expected normalized versionDir/go/VERSION: %s", err)
+			}
+		})
+	}
+}
+
+func TestExtractRejectsEscapingEntries(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	archive := filepath.Join(gb.downloadsDir, "go.tar.gz")
+	writeTarGz(t, archive, map[string]string{"go/bin/go": "go", "go/../../evil": "x"})
+
+	if err := gb.extract(archive, gb.getVersionDir("1.16"), 1); err == nil {
+		t.Error("expected an error for an entry escaping the version dir")
+	}
+}
+
+func TestExtractRejectsEscapingSymlinks(t *testing.T) {
+	outside := tempDir(t)
+	// an absolute link to outside lands evil where the relative ones do
+	for _, linkname := range []string{"../../..", outside, "bin/../../.."} {
+		gb := newTestGoBrew(tempDir(t))
+		dest := filepath.Join(outside, "versions", "1.16")
+		archive := filepath.Join(tempDir(t), "go.tar.gz")
+		f, err := os.Create(archive)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gw := gzip.NewWriter(f)
+		tw := tar.NewWriter(gw)
+		tw.WriteHeader(&tar.Header{Name: "go/bin/go", Mode: 0755, Size: 2, Typeflag: tar.TypeReg})
+		tw.Write([]byte("go"))
+		tw.WriteHeader(&tar.Header{Name: "go/x", Linkname: linkname, Typeflag: tar.TypeSymlink})
+		tw.WriteHeader(&tar.Header{Name: "go/x/evil", Mode: 0644, Size: 4, Typeflag: tar.TypeReg})
+		tw.Write([]byte("evil"))
+		tw.Close()
+		gw.Close()
+		f.Close()
+
+		if err := gb.extract(archive, dest, 0); err == nil {
+			t.Errorf("%s: expected an error for a symlink escaping the version dir", linkname)
+		}
+		if _, err := os.Lstat(filepath.Join(dest, "go", "x")); !os.IsNotExist(err) {
+			t.Errorf("%s: expected the escaping symlink not created, got %v", linkname, err)
+		}
+		if _, err := os.Stat(filepath.Join(outside, "evil")); !os.IsNotExist(err) {
+			t.Errorf("%s: expected nothing written outside the version dir, got %v", linkname, err)
+		}
+	}
+}
+
+// writeToolchainFixture writes a tarball shaped like a go release: nested
+// directories, executables and a symlink
+func writeToolchainFixture(t testing.TB, path string, files int) {
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+	write := func(hdr *tar.Header, content []byte) {
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write(content)
+	}
+	write(&tar.Header{Name: "go/", Mode: 0755, Typeflag: tar.TypeDir}, nil)
+	write(&tar.Header{Name: "go/bin/", Mode: 0755, Typeflag: tar.TypeDir}, nil)
+	write(&tar.Header{Name: "go/bin/go", Mode: 0755, Size: 2, Typeflag: tar.TypeReg}, []byte("go"))
+	for d := 0; d < 10; d++ {
+		dir := fmt.Sprintf("go/src/pkg%d/", d)
+		write(&tar.Header{Name: dir, Mode: 0750, Typeflag: tar.TypeDir}, nil)
+		for i := 0; i < files/10; i++ {
+			content := bytes.Repeat([]byte{byte('a' + i%26)}, 1024+i)
+			write(&tar.Header{Name: fmt.Sprintf("%sfile%d.go", dir, i), Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}, content)
+		}
+	}
+	write(&tar.Header{Name: "go/src/link", Linkname: "pkg0/file0.go", Typeflag: tar.TypeSymlink}, nil)
+	tw.Close()
+	gw.Close()
+}
+
+// snapshot maps every path under root to its mode and content or link target
+func snapshot(t *testing.T, root string) map[string]string {
+	entries := make(map[string]string)
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			t.Fatal(err)
+		}
+		rel, _ := filepath.Rel(root, path)
+		entry := info.Mode().String()
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			target, _ := os.Readlink(path)
+			entry += " -> " + target
+		case info.Mode().IsRegular():
+			content, _ := ioutil.ReadFile(path)
+			entry += " " + string(content)
+		}
+		entries[rel] = entry
+		return nil
+	})
+	return entries
+}
+
+func TestExtractParallelMatchesSequential(t *testing.T) {
+	archive := filepath.Join(tempDir(t), "go.tar.gz")
+	writeToolchainFixture(t, archive, 200)
+
+	sequential := newTestGoBrew(tempDir(t))
+	if err := sequential.extract(archive, sequential.getVersionDir("1.16"), -1); err != nil {
+		t.Fatal(err)
+	}
+	parallel := newTestGoBrew(tempDir(t))
+	parallel.extractWorkers = 8
+	if err := parallel.extract(archive, parallel.getVersionDir("1.16"), -1); err != nil {
+		t.Fatal(err)
+	}
+
+	want := snapshot(t, sequential.getVersionDir("1.16"))
+	got := snapshot(t, parallel.getVersionDir("1.16"))
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parallel extraction differs from sequential extraction")
+	}
+	if len(got) < 200 {
+		t.Errorf("expected the fixture extracted, got %d entries", len(got))
+	}
+}
+
+func benchmarkExtract(b *testing.B, workers int) {
+	dir, _ := ioutil.TempDir("", "gobrew-bench")
+	defer os.RemoveAll(dir)
+	archive := filepath.Join(dir, "go.tar.gz")
+	writeToolchainFixture(b, archive, 2000)
+
+	gb := newTestGoBrew(dir)
+	gb.extractWorkers = workers
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dest := filepath.Join(dir, "versions", fmt.Sprint(i))
+		if err := gb.extract(archive, dest, -1); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkExtractSequential(b *testing.B) { benchmarkExtract(b, 1) }
+func BenchmarkExtractParallel(b *testing.B)   { benchmarkExtract(b, 8) }
+
+func TestExtractVersionFailureLeavesNoVersion(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	archive := filepath.Join(tempDir(t), "go.tar.gz")
+	writeToolchainFixture(t, archive, 200)
+	fi, _ := os.Stat(archive)
+	// cut the archive off after some of its files
+	if err := os.Truncate(archive, fi.Size()/2); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := gb.mkdirs("1.16"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gb.extractVersion(archive, "1.16"); err == nil {
+		t.Fatal("expected a truncated archive to fail")
+	}
+	if gb.existsVersion("1.16") {
+		t.Error("a failed extraction must not look installed")
+	}
+	entries, _ := ioutil.ReadDir(gb.versionsDir)
+	for _, entry := range entries {
+		if entry.Name() != "1.16" {
+			t.Errorf("unexpected leftover %s", entry.Name())
+		}
+	}
+	installed, _ := gb.InstalledVersions()
+	if len(installed) > 1 {
+		t.Errorf("unexpected installed versions %v", installed)
+	}
+}
+
+func TestExtractVersion(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	archive := filepath.Join(tempDir(t), "go.tar.gz")
+	writeTarGz(t, archive, map[string]string{"go/bin/go": "go"})
+
+	if err := gb.mkdirs("1.16"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gb.extractVersion(archive, "1.16"); err != nil {
+		t.Fatal(err)
+	}
+	if !gb.existsVersion("1.16") {
+		t.Error("expected 1.16 installed")
+	}
+	if _, err := os.Stat(filepath.Join(gb.versionsDir, ".tmp-1.16")); !os.IsNotExist(err) {
+		t.Errorf("expected the temporary directory gone, got %v", err)
+	}
+}
+
+func TestExtractVerifiedSinglePass(t *testing.T) {
+	// the default stripComponents, detected while extracting
+	gb := newTestGoBrew(tempDir(t))
+	archive := filepath.Join(tempDir(t), "go.tar.gz")
+	writeToolchainFixture(t, archive, 200)
+	sum, err := fileSHA256(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opens := 0
+	openArchive = func(name string) (*os.File, error) {
+		opens++
+		return os.Open(name)
+	}
+	defer func() { openArchive = os.Open }()
+
+	if err := gb.mkdirs("1.16"); err != nil {
+		t.Fatal(err)
+	}
+	err = gb.extractVerified(archive, "1.16", "0000")
+	if !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("expected ErrChecksumMismatch, got %v", err)
+	}
+	if gb.existsVersion("1.16") {
+		t.Fatal("a mismatching archive must not be installed")
+	}
+	if _, err := os.Stat(filepath.Join(gb.versionsDir, ".tmp-1.16")); !os.IsNotExist(err) {
+		t.Errorf("expected the temporary directory gone, got %v", err)
+	}
+
+	opens = 0
+	if err := gb.extractVerified(archive, "1.16", sum); err != nil {
+		t.Fatal(err)
+	}
+	if !gb.existsVersion("1.16") {
+		t.Error("expected 1.16 installed")
+	}
+	if opens != 1 {
+		t.Errorf("expected verify and extract to share a single read of the archive, got %d", opens)
+	}
+
+	// a corrupt archive is reported as such rather than as a failed untar
+	fi, _ := os.Stat(archive)
+	if err := os.Truncate(archive, fi.Size()/2); err != nil {
+		t.Fatal(err)
+	}
+	gb.cleanVersionDir("1.16")
+	gb.mkdirs("1.16")
+	if err := gb.extractVerified(archive, "1.16", sum); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("expected ErrChecksumMismatch for a truncated archive, got %v", err)
+	}
+}
+
+func TestExtractMinimal(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	gb.minimal = true
+	archive := filepath.Join(tempDir(t), "go.tar.gz")
+	writeTarGz(t, archive, map[string]string{
+		"go/bin/go":                 "go",
+		"go/pkg/tool/linux/compile": "compile",
+		"go/src/fmt/print.go":       "package fmt",
+		"go/test/fixedbugs/a.go":    "package a",
+		"go/api/go1.txt":            "api",
+		"go/doc/go_spec.html":       "spec",
+		"go/misc/srcdoc.txt":        "kept",
+	})
+
+	dest := tempDir(t)
+	if err := gb.extract(archive, dest, -1); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"bin/go", "pkg/tool/linux/compile", "misc/srcdoc.txt"} {
+		if _, err := os.Stat(filepath.Join(dest, "go", name)); err != nil {
+			t.Errorf("expected %s extracted: %s", name, err)
+		}
+	}
+	for _, dir := range minimalDirs {
+		if _, err := os.Stat(filepath.Join(dest, "go", dir)); !os.IsNotExist(err) {
+			t.Errorf("expected %s skipped, got %v", dir, err)
+		}
+	}
+}
+
+func TestExtractVersionTmpDir(t *testing.T) {
+	root := tempDir(t)
+	os.Setenv("GOBREW_ROOT", root)
+	defer os.Unsetenv("GOBREW_ROOT")
+	os.Setenv("GOBREW_TMPDIR", filepath.Join(root, "scratch"))
+	defer os.Unsetenv("GOBREW_TMPDIR")
+	gb, err := NewGoBrew()
+	if err != nil {
+		t.Fatal(err)
+	}
+	gb.stdout, gb.stderr = ioutil.Discard, ioutil.Discard
+	archive := filepath.Join(tempDir(t), "go.tar.gz")
+	writeTarGz(t, archive, map[string]string{"go/bin/go": "go"})
+
+	// leftovers of an interrupted extraction in each place, only the one in
+	// GOBREW_TMPDIR is replaced
+	for _, dir := range []string{gb.tmpDir, gb.versionsDir} {
+		os.MkdirAll(filepath.Join(dir, ".tmp-1.16", "leftover"), os.ModePerm)
+	}
+	if err := gb.mkdirs("1.16"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gb.extractVersion(archive, "1.16"); err != nil {
+		t.Fatal(err)
+	}
+
+	if !gb.existsVersion("1.16") {
+		t.Error("expected 1.16 installed")
+	}
+	if _, err := os.Stat(filepath.Join(gb.tmpDir, ".tmp-1.16")); !os.IsNotExist(err) {
+		t.Errorf("expected the extraction to happen in GOBREW_TMPDIR, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(gb.versionsDir, ".tmp-1.16", "leftover")); err != nil {
+		t.Errorf("expected nothing extracted in the versions dir: %s", err)
+	}
+}
+
+func TestScratchDirFallback(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	var out bytes.Buffer
+	gb.stdout = &out
+	os.MkdirAll(gb.versionsDir, os.ModePerm)
+	notADir := filepath.Join(tempDir(t), "file")
+	ioutil.WriteFile(notADir, nil, 0644)
+
+	gb.tmpDir = filepath.Join(notADir, "scratch")
+	if dir := gb.scratchDir(); dir != gb.versionsDir {
+		t.Errorf("expected the fallback to %s, got %s", gb.versionsDir, dir)
+	}
+	if out.Len() == 0 {
+		t.Error("expected a warning about GOBREW_TMPDIR")
+	}
+}
+
+// recompress rewrites the tar.gz archive at src as dest, compressed by compress
+func recompress(t *testing.T, src string, dest string, compress func(tarball []byte) ([]byte, error)) {
+	t.Helper()
+	f, err := os.Open(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tarball, err := ioutil.ReadAll(gr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compressed, err := compress(tarball)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(dest, compressed, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func xzCompress(tarball []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	xw, err := xz.NewWriter(&buf)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := xw.Write(tarball); err != nil {
+		return nil, err
+	}
+	if err := xw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// bzip2Compress uses the bzip2 command, the standard library only decompresses
+func bzip2Compress(tarball []byte) ([]byte, error) {
+	cmd := exec.Command("bzip2", "-c")
+	cmd.Stdin = bytes.NewReader(tarball)
+	return cmd.Output()
+}
+
+func TestExtractCompressions(t *testing.T) {
+	source := filepath.Join(tempDir(t), "go.tar.gz")
+	writeTarGz(t, source, map[string]string{"go/bin/go": "#!/bin/sh\n", "go/VERSION": "go1.16"})
+
+	tests := []struct {
+		name     string
+		compress func([]byte) ([]byte, error)
+	}{
+		{name: "go.tar.xz", compress: xzCompress},
+		{name: "go.tar.bz2", compress: bzip2Compress},
+		// a mirror repackaging under the official name
+		{name: "go1.16.linux-amd64.tar.gz", compress: xzCompress},
+	}
+	for _, tt := range tests {
+		if strings.HasSuffix(tt.name, ".bz2") {
+			if _, err := exec.LookPath("bzip2"); err != nil {
+				t.Logf("skipping %s: %s", tt.name, err)
+				continue
+			}
+		}
+		gb := newTestGoBrew(tempDir(t))
+		archive := filepath.Join(tempDir(t), tt.name)
+		recompress(t, source, archive, tt.compress)
+
+		if err := gb.extract(archive, gb.getVersionDir("1.16"), -1); err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		content, err := ioutil.ReadFile(filepath.Join(gb.getVersionDir("1.16"), "go", "VERSION"))
+		if err != nil || string(content) != "go1.16" {
+			t.Errorf("%s: got %q, %v", tt.name, content, err)
+		}
+	}
+}
+
+func TestExtractUnsupportedCompression(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	archive := filepath.Join(tempDir(t), "go.zip")
+	ioutil.WriteFile(archive, []byte("PK\x03\x04 not a tarball"), 0644)
+
+	err := gb.extract(archive, gb.getVersionDir("1.16"), -1)
+	if err == nil || !strings.Contains(err.Error(), "unsupported compression") {
+		t.Errorf("expected an unsupported compression error, got %v", err)
+	}
+}
+
+func TestTarXattrs(t *testing.T) {
+	hdr := &tar.Header{PAXRecords: map[string]string{
+		"SCHILY.xattr.com.apple.cs.CodeSignature": "sig",
+		"mtime": "1700000000",
+	}}
+	if got := tarXattrs(hdr); !reflect.DeepEqual(got, map[string]string{"com.apple.cs.CodeSignature": "sig"}) {
+		t.Errorf("unexpected xattrs %v", got)
+	}
+	if got := tarXattrs(&tar.Header{}); got != nil {
+		t.Errorf("expected no xattrs, got %v", got)
+	}
+}
@@ -5,10 +5,11 @@ go 1.17
 require (
 	github.com/Masterminds/semver v1.5.0
 	github.com/fatih/color v1.10.0
+	github.com/ulikunitz/xz v0.5.11
+	golang.org/x/sys v0.0.0-20200223170610-d5e6a3e2c0ae
 )
 
 require (
 	github.com/mattn/go-colorable v0.1.8 // indirect
 	github.com/mattn/go-isatty v0.0.12 // indirect
-	golang.org/x/sys v0.0.0-20200223170610-d5e6a3e2c0ae // indirect
 )
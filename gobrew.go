@@ -1,47 +1,126 @@
 package gobrew
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"regexp"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
 
 	"github.com/Masterminds/semver"
 	"github.com/kevincobain2000/gobrew/utils"
 )
 
 const (
-	goBrewDir     string = ".gobrew"
-	registryPath  string = "https://golang.org/dl/"
-	fetchTagsRepo string = "https://github.com/golang/go"
+	goBrewDir           string = ".gobrew"
+	defaultRegistryPath string = "https://go.dev/dl/" // golang.org/dl redirects here
+	defaultTagsRepo     string = "https://github.com/golang/go"
+	defaultGoToolchain  string = "local"
+
+	defaultGitTimeout = 60 * time.Second
 )
 
+// ErrTimeout is returned when an external command runs past its deadline
+var ErrTimeout = errors.New("timed out")
+
 // Command ...
 type Command interface {
 	ListVersions()
 	ListRemoteVersions()
-	CurrentVersion() string
+	CurrentVersion(name ...string) string
+	IsInstalled(version string) bool
 	Uninstall(version string)
-	Install(version string)
+	Install(version string) InstallResult
 	Use(version string)
 	Helper
 }
 
 // GoBrew struct
 type GoBrew struct {
-	homeDir       string
-	installDir    string
-	versionsDir   string
-	currentDir    string
-	currentBinDir string
-	currentGoDir  string
-	downloadsDir  string
+	homeDir     string
+	installDir  string
+	versionsDir string
+	// systemVersionsDir of a read-only root shared by every user, optional
+	systemVersionsDir string
+	currentDir        string
+	currentBinDir     string
+	currentGoDir      string
+	downloadsDir      string
+	// stripComponents leading path elements dropped from archive entries,
+	// negative to detect them from the archive layout
+	stripComponents int
+	// extractWorkers writing files concurrently while extracting, 1 is sequential
+	extractWorkers int
+	// report receives the progress of install, see InstallWithProgress
+	report func(ProgressEvent)
+	// tmpDir versions are extracted in before being renamed into versionsDir,
+	// versionsDir itself when empty, see scratchDir
+	tmpDir string
+	// goToolchain GOTOOLCHAIN is set to where gobrew sets the environment, so go
+	// 1.21+ doesn't download toolchains itself; empty leaves it alone
+	goToolchain string
+	// minimal skips extracting the source, tests and docs when GOBREW_MINIMAL=1
+	minimal bool
+	// gitTimeout bounds git ls-remote
+	gitTimeout time.Duration
+	// binSubpath of the go binaries in a version dir, slash separated, see
+	// defaultBinSubpath
+	binSubpath string
+	// registryPath the release archives are downloaded from
+	registryPath string
+	// mirrors archives are downloaded from again when the registry serves one
+	// with the wrong checksum
+	mirrors []string
+	// tagsRepo git ls-remote lists the release tags of
+	tagsRepo string
+	// offline refuses network access when GOBREW_NO_NETWORK=1, installs only
+	// succeed from cached archives
+	offline bool
+	// downloader, aria2c or curl, fetching archives instead of the built-in client
+	downloader string
+	// releasesURL of the JSON API listing releases and their files
+	releasesURL string
+	// releasesCacheTTL the JSON API response is used for before revalidating it
+	releasesCacheTTL time.Duration
+	// autoInstall lets Use install a missing version instead of failing
+	autoInstall bool
+	// verifyExisting makes Install verify an existing version instead of
+	// trusting the directory is there, see EnableVerifyExisting
+	verifyExisting bool
+	// removeSuperseded makes UpgradeAll remove the patch it upgraded from, see
+	// EnableRemoveSuperseded
+	removeSuperseded bool
+	// cleanupOnSignal removes a partial install when interrupted, see EnableSignalCleanup
+	cleanupOnSignal bool
+	// downloadMaxAge after which Install evicts leftover downloads, 0 never does
+	downloadMaxAge time.Duration
+	// keepDownloads leaves archives in the downloads dir after installs, to be
+	// reused on reinstall, see EnableKeepDownloads
+	keepDownloads bool
+	// downloadCacheMax bytes the downloads dir is trimmed to after installs, 0 is unbounded
+	downloadCacheMax int64
+	// metrics records each command to a local file when GOBREW_METRICS=1
+	metrics bool
+	// downloaded bytes so far, for the metrics
+	downloaded int64
+	// stdout and stderr receive user facing messages, without timestamps
+	stdout io.Writer
+	stderr io.Writer
+	// debug logs timestamped diagnostics when GOBREW_DEBUG=1
+	debug *log.Logger
+	// envSources are the env vars NewGoBrew took a setting from, see EffectiveConfig
+	envSources map[string]bool
 	Command
 }
 
@@ -50,44 +129,223 @@ type Helper interface {
 	getArch() string
 	existsVersion(version string) bool
 	cleanVersionDir(version string)
-	mkdirs(version string)
+	extract(archive string, dest string, stripComponents int) error
+	mkdirs(version string) error
 	getVersionDir(version string) string
-	downloadAndExtract(version string)
+	downloadAndExtract(version string) error
 	changeSymblinkGoBin(version string)
 	changeSymblinkGo(version string)
 }
 
-var gb GoBrew
-
-// NewGoBrew instance
-func NewGoBrew() GoBrew {
-	gb.homeDir = os.Getenv("HOME")
+// NewGoBrew instance, configured from the env. It fails when there is no
+// home dir to put the root in, or the root is not a writable directory
+func NewGoBrew() (GoBrew, error) {
+	var gb GoBrew
+	home, homeErr := os.UserHomeDir()
+	gb.homeDir = home
 	gb.installDir = filepath.Join(gb.homeDir, goBrewDir)
+	if root := expandPath(os.Getenv("GOBREW_ROOT"), gb.homeDir); root != "" {
+		if filepath.IsAbs(root) {
+			gb.installDir = root
+			gb.fromEnv("GOBREW_ROOT")
+		} else {
+			utils.ColorError.Fprintf(os.Stderr, "[Error] GOBREW_ROOT %s is not absolute, using %s\n", root, gb.installDir)
+		}
+	}
+	if !filepath.IsAbs(gb.installDir) {
+		return gb, fmt.Errorf("cannot determine the home directory, set HOME or GOBREW_ROOT: %s", homeErr)
+	}
+	if err := validateRoot(gb.installDir); err != nil {
+		return gb, err
+	}
 	gb.versionsDir = filepath.Join(gb.installDir, "versions")
+	gb.systemVersionsDir = ""
+	if root := os.Getenv("GOBREW_SYSTEM_ROOT"); root != "" {
+		gb.systemVersionsDir = filepath.Join(root, "versions")
+		gb.fromEnv("GOBREW_SYSTEM_ROOT")
+	}
 	gb.currentDir = filepath.Join(gb.installDir, "current")
 	gb.currentBinDir = filepath.Join(gb.installDir, "current", "bin")
 	gb.currentGoDir = filepath.Join(gb.installDir, "current", "go")
 	gb.downloadsDir = filepath.Join(gb.installDir, "downloads")
+	if dir := os.Getenv("GOBREW_DOWNLOAD_DIR"); dir != "" {
+		gb.downloadsDir = dir
+		gb.fromEnv("GOBREW_DOWNLOAD_DIR")
+	}
+	gb.binSubpath = defaultBinSubpath
+	if subpath := os.Getenv("GOBREW_BIN_SUBPATH"); subpath != "" {
+		parsed, err := parseBinSubpath(subpath)
+		if err != nil {
+			return gb, err
+		}
+		gb.binSubpath = parsed
+		gb.fromEnv("GOBREW_BIN_SUBPATH")
+	}
+	gb.stripComponents = -1
+	if strip, err := strconv.Atoi(os.Getenv("GOBREW_STRIP_COMPONENTS")); err == nil {
+		gb.stripComponents = strip
+		gb.fromEnv("GOBREW_STRIP_COMPONENTS")
+	}
+	gb.extractWorkers = 1
+	if workers, err := strconv.Atoi(os.Getenv("GOBREW_EXTRACT_WORKERS")); err == nil {
+		gb.extractWorkers = workers
+		gb.fromEnv("GOBREW_EXTRACT_WORKERS")
+	}
+	gb.tmpDir = expandPath(os.Getenv("GOBREW_TMPDIR"), gb.homeDir)
+	if gb.tmpDir != "" {
+		gb.fromEnv("GOBREW_TMPDIR")
+	}
+	gb.gitTimeout = defaultGitTimeout
+	if timeout, err := time.ParseDuration(os.Getenv("GOBREW_GIT_TIMEOUT")); err == nil {
+		gb.gitTimeout = timeout
+		gb.fromEnv("GOBREW_GIT_TIMEOUT")
+	}
+	gb.registryPath = defaultRegistryPath
+	if registry := os.Getenv("GOBREW_REGISTRY"); registry != "" {
+		gb.registryPath = registry
+		gb.fromEnv("GOBREW_REGISTRY")
+	}
+	gb.mirrors = parseMirrors(os.Getenv("GOBREW_MIRRORS"))
+	if len(gb.mirrors) > 0 {
+		gb.fromEnv("GOBREW_MIRRORS")
+	}
+	gb.tagsRepo = defaultTagsRepo
+	if repo := os.Getenv("GOBREW_TAGS_REPO"); repo != "" {
+		gb.tagsRepo = repo
+		gb.fromEnv("GOBREW_TAGS_REPO")
+	}
+	gb.downloader = os.Getenv("GOBREW_DOWNLOADER")
+	if gb.downloader != "" {
+		gb.fromEnv("GOBREW_DOWNLOADER")
+	}
+	gb.offline = gb.envFlag("GOBREW_NO_NETWORK")
+	gb.releasesURL = defaultReleasesURL
+	if releasesURL := os.Getenv("GOBREW_RELEASES_URL"); releasesURL != "" {
+		gb.releasesURL = releasesURL
+		gb.fromEnv("GOBREW_RELEASES_URL")
+	}
+	gb.releasesCacheTTL = defaultReleasesCacheTTL
+	if ttl, err := time.ParseDuration(os.Getenv("GOBREW_CACHE_TTL")); err == nil {
+		gb.releasesCacheTTL = ttl
+		gb.fromEnv("GOBREW_CACHE_TTL")
+	}
+	if max, err := strconv.ParseInt(os.Getenv("GOBREW_DOWNLOAD_CACHE_MAX"), 10, 64); err == nil {
+		gb.downloadCacheMax = max
+		gb.fromEnv("GOBREW_DOWNLOAD_CACHE_MAX")
+	}
+	gb.keepDownloads = gb.envFlag("GOBREW_KEEP_DOWNLOADS")
+	gb.minimal = gb.envFlag("GOBREW_MINIMAL")
+	gb.goToolchain = defaultGoToolchain
+	if toolchain, ok := os.LookupEnv("GOBREW_GOTOOLCHAIN"); ok {
+		gb.goToolchain = toolchain
+		gb.fromEnv("GOBREW_GOTOOLCHAIN")
+	}
+	gb.downloadMaxAge = defaultDownloadMaxAge
+	if maxAge, err := time.ParseDuration(os.Getenv("GOBREW_DOWNLOAD_MAX_AGE")); err == nil {
+		gb.downloadMaxAge = maxAge
+		gb.fromEnv("GOBREW_DOWNLOAD_MAX_AGE")
+	}
+	gb.autoInstall = gb.envFlag("GOBREW_AUTO_INSTALL")
+	gb.metrics = gb.envFlag("GOBREW_METRICS")
+	gb.stdout = os.Stdout
+	gb.stderr = os.Stderr
+	gb.debug = log.New(ioutil.Discard, "", 0)
+	if gb.envFlag("GOBREW_DEBUG") {
+		gb.debug = log.New(os.Stderr, "[Debug] ", log.LstdFlags)
+	}
+
+	return gb, nil
+}
+
+// validateRoot checks root is a writable directory, when it exists. A missing
+// root is created by the first install
+func validateRoot(root string) error {
+	fi, err := os.Stat(root)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !fi.IsDir() {
+		return fmt.Errorf("root %s is not a directory", root)
+	}
+	probe, err := ioutil.TempFile(root, ".probe-")
+	if err != nil {
+		return fmt.Errorf("root %s is not writable: %w", root, err)
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
 
-	return gb
+// expandPath expands environment variables and a leading ~ in path, shells
+// don't when the value is quoted or set from a config file
+func expandPath(path, home string) string {
+	path = os.ExpandEnv(path)
+	if path == "~" {
+		return home
+	}
+	if strings.HasPrefix(path, "~/") {
+		return filepath.Join(home, path[2:])
+	}
+	return path
 }
 
 func (gb *GoBrew) getArch() string {
-	return runtime.GOOS + "-" + runtime.GOARCH
+	return platform(runtime.GOOS, runtime.GOARCH)
 }
 
-// ListVersions that are installed by dir ls
-// highlight the version that is currently symbolic linked
-func (gb *GoBrew) ListVersions() {
+// VersionInfo describes an installed version
+type VersionInfo struct {
+	Version string
+	Current bool
+	// Size and InstalledAt are only filled in when asked for, see ListVersionsTable
+	Size        int64
+	InstalledAt time.Time
+	// dir the version is installed in
+	dir string
+}
+
+// DiskSize returns Size when known, otherwise walks the version directory,
+// which is expensive for a full toolchain
+func (v VersionInfo) DiskSize() (int64, error) {
+	if v.Size > 0 || v.dir == "" {
+		return v.Size, nil
+	}
+	return utils.DirSize(v.dir)
+}
+
+// ErrStopWalk stops WalkInstalled early without reporting an error
+var ErrStopWalk = errors.New("stop walk")
+
+// InstalledVersions returns the versions found in versionsDir and the system
+// root, semantic versions sorted first followed by rc and beta versions, then
+// external and commit builds.
+// A fresh root without a versions dir has none
+func (gb *GoBrew) InstalledVersions() ([]string, error) {
 	files, err := ioutil.ReadDir(gb.versionsDir)
-	if err != nil {
-		utils.ColorError.Printf("[Error]: List versions failed: %s", err)
-		os.Exit(0)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if gb.systemVersionsDir != "" {
+		systemFiles, err := ioutil.ReadDir(gb.systemVersionsDir)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		files = mergeFileInfos(files, systemFiles)
 	}
-	cv := gb.CurrentVersion()
 
 	versionsSemantic := make([]*semver.Version, 0)
 
+	// skip extractions in progress
+	visible := files[:0]
+	for _, f := range files {
+		if !strings.HasPrefix(f.Name(), ".") {
+			visible = append(visible, f)
+		}
+	}
+	files = visible
+
 	for _, f := range files {
 		v, err := semver.NewVersion(f.Name())
 		if err != nil {
@@ -100,71 +358,332 @@ func (gb *GoBrew) ListVersions() {
 	// sort semantic versions
 	sort.Sort(semver.Collection(versionsSemantic))
 
+	versions := make([]string, 0, len(files))
 	for _, versionSemantic := range versionsSemantic {
-		version := versionSemantic.String()
-		// 1.8.0 -> 1.8
-		reMajorVersion, _ := regexp.Compile("[0-9]+.[0-9]+.0")
-		if reMajorVersion.MatchString((version)) {
-			version = strings.Split(version, ".")[0] + "." + strings.Split(version, ".")[1]
+		versions = append(versions, versionSemantic.Original())
+	}
+
+	// rc and beta versions in the end, followed by external and commit builds
+	r, _ := regexp.Compile("beta.*|rc.*")
+	builds := make([]string, 0)
+	for _, f := range files {
+		if isExternalVersion(f.Name()) || isCommitVersion(f.Name()) {
+			builds = append(builds, f.Name())
+			continue
+		}
+		matches := r.FindAllString(f.Name(), -1)
+		if len(matches) == 1 {
+			versions = append(versions, f.Name())
 		}
+	}
+	return append(versions, builds...), nil
+}
 
-		if version == cv {
-			version = cv + "*"
-			utils.ColorSuccess.Println(version)
+// mergeFileInfos appends the entries of extra not named in files
+func mergeFileInfos(files []os.FileInfo, extra []os.FileInfo) []os.FileInfo {
+	names := make(map[string]bool)
+	for _, f := range files {
+		names[f.Name()] = true
+	}
+	for _, f := range extra {
+		if !names[f.Name()] {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// VersionStatus returns the installed versions with the current one marked
+func (gb *GoBrew) VersionStatus() ([]VersionInfo, error) {
+	versions, err := gb.InstalledVersions()
+	if err != nil {
+		return nil, err
+	}
+	cv := gb.CurrentVersion()
+
+	infos := make([]VersionInfo, 0, len(versions))
+	for _, version := range versions {
+		infos = append(infos, gb.versionInfo(version, cv))
+	}
+	return infos, nil
+}
+
+// WalkInstalled calls fn for each installed version in order, without
+// computing sizes up front. It stops at the first error fn returns, which is
+// passed on unless it is ErrStopWalk.
+func (gb *GoBrew) WalkInstalled(fn func(VersionInfo) error) error {
+	versions, err := gb.InstalledVersions()
+	if err != nil {
+		return err
+	}
+	cv := gb.CurrentVersion()
+
+	for _, version := range versions {
+		if err := fn(gb.versionInfo(version, cv)); err != nil {
+			if err == ErrStopWalk {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (gb *GoBrew) versionInfo(version string, cv string) VersionInfo {
+	return VersionInfo{Version: version, Current: version == cv, dir: gb.installedVersionDir(version)}
+}
+
+// ListVersions that are installed by dir ls
+// highlight the version that is currently symbolic linked
+func (gb *GoBrew) ListVersions() {
+	infos, err := gb.VersionStatus()
+	if err != nil {
+		utils.ColorError.Fprintf(gb.stderr, "[Error]: List versions failed: %s", err)
+		os.Exit(0)
+	}
+	if len(infos) == 0 {
+		utils.ColorInfo.Fprintln(gb.stdout, "[Info] No versions installed, install one with: gobrew install <version>")
+		return
+	}
+
+	cv := ""
+	for _, info := range infos {
+		if info.Current {
+			cv = info.Version
+			utils.ColorSuccess.Fprintln(gb.stdout, info.Version+"*")
 		} else {
-			log.Println(version)
+			fmt.Fprintln(gb.stdout, info.Version)
 		}
 	}
 
-	// print rc and beta versions in the end
-	for _, f := range files {
-		rcVersion := f.Name()
-		r, _ := regexp.Compile("beta.*|rc.*")
-		matches := r.FindAllString(rcVersion, -1)
-		if len(matches) == 1 {
-			if rcVersion == cv {
-				rcVersion = cv + "*"
-				utils.ColorSuccess.Println(rcVersion)
+	if cv != "" {
+		fmt.Fprintln(gb.stdout)
+		fmt.Fprintf(gb.stdout, "current: %s\n", cv)
+	}
+}
+
+// ListPlain writes the installed versions one per line, oldest first, without
+// markers or colors so the output can be piped
+func (gb *GoBrew) ListPlain() error {
+	versions, err := gb.InstalledVersions()
+	if err != nil {
+		return err
+	}
+	sortVersions(versions)
+	for _, version := range versions {
+		fmt.Fprintln(gb.stdout, version)
+	}
+	return nil
+}
+
+// otherGroup holds the versions GroupByMinor can't parse, e.g. external ones
+const otherGroup = "other"
+
+// GroupByMinor groups versions by their minor line, e.g. 1.19 for 1.19.5 and
+// 1.19rc1, each group sorted oldest first
+func GroupByMinor(versions []string) map[string][]string {
+	groups := make(map[string][]string)
+	for _, version := range versions {
+		key := otherGroup
+		if v, ok := parseGoVersion(version); ok {
+			key = fmt.Sprintf("%d.%d", v.major, v.minor)
+		}
+		groups[key] = append(groups[key], version)
+	}
+	for _, group := range groups {
+		sortVersions(group)
+	}
+	return groups
+}
+
+// ListGrouped writes the installed versions one minor line per row, the
+// current version marked with *
+func (gb *GoBrew) ListGrouped() error {
+	versions, err := gb.InstalledVersions()
+	if err != nil {
+		return err
+	}
+	current := gb.CurrentVersion()
+	groups := GroupByMinor(versions)
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sortVersions(keys)
+
+	for _, key := range keys {
+		utils.ColorMajorVersion.Fprint(gb.stdout, key)
+		fmt.Fprint(gb.stdout, "\t")
+		for _, version := range groups[key] {
+			if version == current {
+				utils.ColorSuccess.Fprint(gb.stdout, version+"*  ")
 			} else {
-				log.Println(rcVersion)
+				fmt.Fprint(gb.stdout, version+"  ")
 			}
 		}
+		fmt.Fprintln(gb.stdout)
 	}
+	return nil
+}
 
-	if cv != "" {
-		log.Println()
-		log.Printf("current: %s", cv)
+// ListVersionsTable writes the installed versions to w as aligned columns of
+// version, current marker, size on disk and installed date
+func (gb *GoBrew) ListVersionsTable(w io.Writer) error {
+	infos, err := gb.VersionStatus()
+	if err != nil {
+		return err
+	}
+	for i := range infos {
+		versionDir := gb.installedVersionDir(infos[i].Version)
+		if fi, err := os.Stat(versionDir); err == nil {
+			infos[i].InstalledAt = fi.ModTime()
+		}
+		if size, err := infos[i].DiskSize(); err == nil {
+			infos[i].Size = size
+		}
+	}
+	return renderVersionTable(w, infos)
+}
+
+func renderVersionTable(w io.Writer, infos []VersionInfo) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tCURRENT\tSIZE\tINSTALLED")
+	for _, info := range infos {
+		current := ""
+		if info.Current {
+			current = "*"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", info.Version, current, utils.HumanSize(info.Size), info.InstalledAt.Format("2006-01-02 15:04"))
+	}
+	return tw.Flush()
+}
+
+// ParseListFormat parses a text/template applied to each VersionInfo
+// when listing, e.g. '{{.Version}} {{.Current}}'
+func ParseListFormat(format string) (*template.Template, error) {
+	tmpl, err := template.New("list").Parse(format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --format template: %s", err)
+	}
+	return tmpl, nil
+}
+
+// ListVersionsFormat renders every installed version through tmpl, one per line
+func (gb *GoBrew) ListVersionsFormat(w io.Writer, tmpl *template.Template) error {
+	infos, err := gb.VersionStatus()
+	if err != nil {
+		return err
+	}
+	return renderVersions(w, tmpl, infos)
+}
+
+func renderVersions(w io.Writer, tmpl *template.Template, infos []VersionInfo) error {
+	for _, info := range infos {
+		if err := tmpl.Execute(w, info); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
 // ListRemoteVersions that are installed by dir ls
 func (gb *GoBrew) ListRemoteVersions() {
-	log.Println("[Info]: Fetching remote versions")
-	cmd := exec.Command(
-		"git",
-		"ls-remote",
-		// "--sort=version:refname",
-		"--tags",
-		fetchTagsRepo,
-		"go*")
-	output, err := cmd.CombinedOutput()
+	fmt.Fprintln(gb.stdout, "[Info]: Fetching remote versions")
+	versions, err := gb.RemoteVersions()
 	if err != nil {
-		utils.ColorError.Printf("[Error]: List remote versions failed: %s", err)
+		utils.ColorError.Fprintf(gb.stderr, "[Error]: List remote versions failed: %s", err)
 		os.Exit(0)
 	}
-	tagsRaw := utils.BytesToString(output)
+	printGroupedVersions(gb.stdout, versions)
+}
+
+// RemoteVersions available for download, sorted oldest first. They are read
+// from both the JSON API and the go repository tags, so that either one being
+// down still lists versions; it fails only when both do
+func (gb *GoBrew) RemoteVersions() ([]string, error) {
+	released, apiErr := gb.releasedVersions()
+	if apiErr != nil {
+		gb.debug.Printf("listing versions from the JSON API failed, using git tags only: %s", apiErr)
+	}
+	tagged, gitErr := gb.taggedVersions()
+	if gitErr != nil {
+		if apiErr != nil {
+			return nil, fmt.Errorf("listing remote versions: JSON API: %s, git: %w", apiErr, gitErr)
+		}
+		gb.debug.Printf("listing versions from git tags failed, using the JSON API only: %s", gitErr)
+	}
+	return mergeVersions(released, tagged), nil
+}
+
+// taggedVersions reads the release tags of the go repository, sorted oldest first
+func (gb *GoBrew) taggedVersions() ([]string, error) {
+	if gb.offline {
+		return nil, ErrOfflineMode
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), gb.gitTimeout)
+	defer cancel()
+
+	output, err := gb.lsRemoteTags(ctx, true)
+	sorted := err == nil
+	if err != nil && ctx.Err() == nil {
+		// older git doesn't know --sort=version:refname, sort in process instead
+		gb.debug.Printf("git ls-remote --sort failed, retrying unsorted: %s", err)
+		output, err = gb.lsRemoteTags(ctx, false)
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("%w: git ls-remote did not finish within %s, raise GOBREW_GIT_TIMEOUT on slow networks", ErrTimeout, gb.gitTimeout)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	versions := parseRemoteTags(output)
+	if !sorted {
+		sortVersions(versions)
+	}
+	return versions, nil
+}
+
+func (gb *GoBrew) lsRemoteTags(ctx context.Context, sortByVersion bool) (string, error) {
+	args := []string{"ls-remote"}
+	if sortByVersion {
+		args = append(args, "--sort=version:refname")
+	}
+	args = append(args, "--tags", gb.tagsRepo, "go*")
+	return gb.git(ctx, args...)
+}
+
+// mergeVersions returns the versions of both lists once each, sorted oldest first
+func mergeVersions(a []string, b []string) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	merged := make([]string, 0, len(a)+len(b))
+	for _, version := range append(append([]string(nil), a...), b...) {
+		if !seen[version] {
+			seen[version] = true
+			merged = append(merged, version)
+		}
+	}
+	sortVersions(merged)
+	return merged
+}
+
+// parseRemoteTags extracts versions from `git ls-remote --tags` output
+func parseRemoteTags(tagsRaw string) []string {
 	r, _ := regexp.Compile("tags/go.*")
 
 	matches := r.FindAllString(tagsRaw, -1)
-	versions := make([]string, len(matches))
+	versions := make([]string, 0, len(matches))
 	for _, match := range matches {
 		versionTag := strings.ReplaceAll(match, "tags/go", "")
 		versions = append(versions, versionTag)
 	}
-	printGroupedVersions(versions)
+	return versions
 }
 
-func printGroupedVersions(versions []string) {
+func printGroupedVersions(w io.Writer, versions []string) {
 	groupedVersions := make(map[string][]string)
 	for _, version := range versions {
 		parts := strings.Split(version, ".")
@@ -211,11 +730,11 @@ func printGroupedVersions(versions []string) {
 		lookupKey = versionParts[0] + "." + versionParts[1]
 		// On match 1.0.0, print 1. On match 2.0.0 print 2
 		if reTopVersion.MatchString((strKey)) {
-			utils.ColorMajorVersion.Print(versionParts[0])
-			fmt.Print("\t")
+			utils.ColorMajorVersion.Fprint(w, versionParts[0])
+			fmt.Fprint(w, "\t")
 		} else {
-			utils.ColorMajorVersion.Print(lookupKey)
-			fmt.Print("\t")
+			utils.ColorMajorVersion.Fprint(w, lookupKey)
+			fmt.Fprint(w, "\t")
 		}
 
 		groupedVersionsSemantic := make([]*semver.Version, 0)
@@ -232,7 +751,7 @@ func printGroupedVersions(versions []string) {
 		sort.Sort(semver.Collection(groupedVersionsSemantic))
 
 		for _, gvSemantic := range groupedVersionsSemantic {
-			fmt.Print(gvSemantic.String() + "  ")
+			fmt.Fprint(w, gvSemantic.String()+"  ")
 		}
 
 		// print rc and beta versions in the end
@@ -240,16 +759,36 @@ func printGroupedVersions(versions []string) {
 			r, _ := regexp.Compile("beta.*|rc.*")
 			matches := r.FindAllString(rcVersion, -1)
 			if len(matches) == 1 {
-				fmt.Print(rcVersion + "  ")
+				fmt.Fprint(w, rcVersion+"  ")
 			}
 		}
-		fmt.Println()
+		fmt.Fprintln(w)
 	}
 }
 
+// IsInstalled reports whether version is installed, in the user or the
+// system root. version may be an alias or spelled as go1.17 or 1.22-rc1
+func (gb *GoBrew) IsInstalled(version string) bool {
+	return gb.existsVersion(gb.versionName(version))
+}
+
+// versionName resolves version as given by the user to the name it is
+// installed under: aliases are resolved and the spelling normalized
+func (gb *GoBrew) versionName(version string) string {
+	version = gb.resolveAlias(version)
+	if isCommitVersion(version) {
+		if commit, err := CommitVersion(version); err == nil {
+			return commit
+		}
+	}
+	if normalized, err := normalizeVersion(version); err == nil {
+		return normalized
+	}
+	return version
+}
+
 func (gb *GoBrew) existsVersion(version string) bool {
-	path := filepath.Join(gb.versionsDir, version, "go")
-	_, err := os.Stat(path)
+	_, err := os.Stat(gb.versionGoroot(version))
 	if err == nil {
 		return true
 	}
@@ -259,36 +798,52 @@ func (gb *GoBrew) existsVersion(version string) bool {
 	return false
 }
 
-// CurrentVersion get current version from symb link
-func (gb *GoBrew) CurrentVersion() string {
+// CurrentVersion get current version from symb link, of the named current
+// created by UseNamed when a name is given
+func (gb *GoBrew) CurrentVersion(name ...string) string {
+	binDir := gb.currentBinDir
+	if len(name) > 0 && name[0] != "" {
+		binDir = filepath.Join(gb.installDir, name[0], "bin")
+	}
 
-	fp, err := filepath.EvalSymlinks(gb.currentBinDir)
+	fp, err := resolveLink(binDir)
 	if err != nil {
+		if binDir == gb.currentBinDir && gb.systemSelected() {
+			return systemVersion
+		}
 		return ""
 	}
 
-	version := strings.ReplaceAll(fp, "/go/bin", "")
-	version = strings.ReplaceAll(version, gb.versionsDir, "")
-	version = strings.ReplaceAll(version, "/", "")
-	return version
+	// <versions dir>/<version>/<bin subpath>, in the user or the system root
+	versionDir := fp
+	for range strings.Split(gb.binSubpath, "/") {
+		versionDir = filepath.Dir(versionDir)
+	}
+	return filepath.Base(versionDir)
 }
 
 // Uninstall the given version of go
 func (gb *GoBrew) Uninstall(version string) {
 	if version == "" {
-		log.Fatal("[Error] No version provided")
+		utils.ColorError.Fprintln(gb.stderr, "[Error] No version provided")
+		os.Exit(1)
 	}
 	if gb.CurrentVersion() == version {
-		utils.ColorError.Printf("[Error] Version: %s you are trying to remove is your current version. Please use a different version first before uninstalling the current version\n", version)
+		utils.ColorError.Fprintf(gb.stderr, "[Error] Version: %s you are trying to remove is your current version. Please use a different version first before uninstalling the current version\n", version)
 		os.Exit(0)
 		return
 	}
 	if !gb.existsVersion(version) {
-		utils.ColorError.Printf("[Error] Version: %s you are trying to remove is not installed\n", version)
+		utils.ColorError.Fprintf(gb.stderr, "[Error] Version: %s you are trying to remove is not installed\n", version)
+		os.Exit(0)
+	}
+	if gb.isSystemVersion(version) {
+		utils.ColorError.Fprintf(gb.stderr, "[Error] Version: %s is installed in the read-only system root %s\n", version, gb.systemVersionsDir)
 		os.Exit(0)
 	}
 	gb.cleanVersionDir(version)
-	utils.ColorSuccess.Printf("[Success] Version: %s uninstalled\n", version)
+	utils.ColorSuccess.Fprintf(gb.stdout, "[Success] Version: %s uninstalled\n", version)
+	gb.dropReferences(version)
 }
 
 func (gb *GoBrew) cleanVersionDir(version string) {
@@ -296,107 +851,364 @@ func (gb *GoBrew) cleanVersionDir(version string) {
 }
 
 func (gb *GoBrew) cleanDownloadsDir() {
+	if gb.downloadsDir != filepath.Join(gb.installDir, "downloads") {
+		// GOBREW_DOWNLOAD_DIR may be a shared cache, only remove our archives
+		archives, _ := filepath.Glob(filepath.Join(gb.downloadsDir, "go*.tar.gz"))
+		for _, archive := range archives {
+			os.Remove(archive)
+		}
+		return
+	}
 	os.RemoveAll(gb.downloadsDir)
 }
 
-// Install the given version of go
-func (gb *GoBrew) Install(version string) {
+// withDownloadsLock runs fn holding the lock of the shared downloads dir
+func (gb *GoBrew) withDownloadsLock(fn func() error) error {
+	unlock, err := gb.lock(downloadsLock)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fn()
+}
+
+// Install the given version of go, rc and beta versions included, reporting
+// whether it was already there and what was downloaded
+func (gb *GoBrew) Install(version string) InstallResult {
+	result, err := gb.install(version)
+	if err != nil {
+		code := 1
+		var failed installError
+		if errors.As(err, &failed) {
+			code = failed.code
+		}
+		osExit(code)
+	}
+	return result
+}
+
+// installError is an install failure already reported on stderr, exiting
+// Install with code
+type installError struct {
+	code int
+	err  error
+}
+
+func (e installError) Error() string { return e.err.Error() }
+
+func (e installError) Unwrap() error { return e.err }
+
+// installCause strips the exit code Install would use from err
+func installCause(err error) error {
+	var failed installError
+	if errors.As(err, &failed) {
+		return failed.err
+	}
+	return err
+}
+
+// install is Install returning its failure, once reported, instead of
+// exiting. Progress goes to gb.report when set, see InstallWithProgress
+func (gb *GoBrew) install(version string) (InstallResult, error) {
 	if version == "" {
-		log.Fatal("[Error] No version provided")
+		utils.ColorError.Fprintln(gb.stderr, "[Error] No version provided")
+		return InstallResult{}, fmt.Errorf("no version provided")
+	}
+	if isCommitVersion(version) {
+		return gb.installCommit(version)
+	}
+	version, err := normalizeVersion(version)
+	if err != nil {
+		utils.ColorError.Fprintf(gb.stderr, "[Error] %s\n", err)
+		return InstallResult{}, err
+	}
+	result := InstallResult{Version: version}
+	if err := gb.mkdirs(version); err != nil {
+		utils.ColorError.Fprintf(gb.stderr, "[Error] %s\n", err)
+		return result, err
+	}
+	unlock, err := gb.lock(version)
+	if err != nil {
+		utils.ColorError.Fprintf(gb.stderr, "[Error] Locking version %s: %s\n", version, err)
+		return result, err
+	}
+	defer unlock()
+	if gb.downloadMaxAge > 0 && !gb.keepDownloads {
+		if err := gb.withDownloadsLock(func() error { return gb.EvictDownloads(gb.downloadMaxAge) }); err != nil {
+			gb.debug.Printf("evicting downloads: %s", err)
+		}
 	}
-	gb.mkdirs(version)
 	if gb.existsVersion(version) {
-		utils.ColorInfo.Printf("[Info] Version: %s exists \n", version)
-		return
+		if !gb.verifyExisting {
+			utils.ColorInfo.Fprintf(gb.stdout, "[Info] Version: %s exists \n", version)
+			return InstallResult{Version: version, AlreadyInstalled: true}, nil
+		}
+		err := gb.Verify(version)
+		if err == nil {
+			utils.ColorInfo.Fprintf(gb.stdout, "[Info] Version: %s exists and is verified \n", version)
+			return InstallResult{Version: version, AlreadyInstalled: true}, nil
+		}
+		utils.ColorInfo.Fprintf(gb.stdout, "[Info] Reinstalling version: %s, %s \n", version, err)
+		gb.cleanVersionDir(version)
+		if err := gb.mkdirs(version); err != nil {
+			utils.ColorError.Fprintf(gb.stderr, "[Error] %s\n", err)
+			return result, err
+		}
+	}
+
+	if gb.cleanupOnSignal {
+		stop := gb.watchSignals(version)
+		defer stop()
+	}
+
+	if gb.minimal {
+		utils.ColorInfo.Fprintf(gb.stdout, "[Info] GOBREW_MINIMAL=1: %s are not extracted. go build needs src from go1.20 on, as the standard library is no longer precompiled\n", strings.Join(minimalDirs, ", "))
+	}
+	if !knownPlatforms[gb.getArch()] {
+		utils.ColorInfo.Fprintf(gb.stdout, "[Info] %s is not a known platform, it may have no builds\n", gb.getArch())
+	}
+	if err := gb.checkHostBuild(version); err != nil {
+		gb.cleanVersionDir(version)
+		utils.ColorError.Fprintf(gb.stderr, "[Error] %s\n", err)
+		return result, err
+	}
+	utils.ColorInfo.Fprintf(gb.stdout, "[Info] Downloading version: %s \n", version)
+	downloaded := gb.downloaded
+	if err := gb.downloadAndExtract(version); err != nil {
+		// reported and cleaned up by downloadAndExtract
+		return result, installError{code: 0, err: err}
 	}
+	result.BytesDownloaded = gb.downloaded - downloaded
+	result.Downloaded = result.BytesDownloaded > 0
+	// other versions may be downloading, only remove our archive
+	gb.withDownloadsLock(func() error {
+		if !gb.keepDownloads {
+			os.Remove(filepath.Join(gb.downloadsDir, gb.archiveName(version)))
+		}
+		if gb.downloadCacheMax > 0 {
+			return gb.TrimDownloadCache(gb.downloadCacheMax)
+		}
+		return nil
+	})
+	if err := gb.checkBinSubpath(version); err != nil {
+		gb.cleanVersionDir(version)
+		utils.ColorError.Fprintf(gb.stderr, "[Error] %s\n", err)
+		return result, err
+	}
+	utils.ColorSuccess.Fprintf(gb.stdout, "[Success] Downloaded version: %s\n", version)
+	gb.runPostInstallHook(version)
+	return result, nil
+}
 
-	utils.ColorInfo.Printf("[Info] Downloading version: %s \n", version)
-	gb.downloadAndExtract(version)
-	gb.cleanDownloadsDir()
-	utils.ColorSuccess.Printf("[Success] Downloaded version: %s\n", version)
+// EnableAutoInstall makes Use install a missing version before switching to
+// it, like GOBREW_AUTO_INSTALL=1, instead of failing
+func (gb *GoBrew) EnableAutoInstall() {
+	gb.autoInstall = true
 }
 
-// Use a version
+// Use a version, alias or the default, installing it first when missing if
+// auto install is enabled. Using system removes the current links instead
 func (gb *GoBrew) Use(version string) {
+	version = gb.versionName(version)
+	if version == systemVersion {
+		gb.useSystem()
+		return
+	}
 	if gb.CurrentVersion() == version {
-		utils.ColorInfo.Printf("[Info] Version: %s is already your current version \n", version)
+		utils.ColorInfo.Fprintf(gb.stdout, "[Info] Version: %s is already your current version \n", version)
+		return
+	}
+	if !gb.existsVersion(version) {
+		if !gb.autoInstall {
+			utils.ColorError.Fprintf(gb.stderr, "[Error] Version: %s is not installed. Please install it first or set GOBREW_AUTO_INSTALL=1\n", version)
+			os.Exit(0)
+		}
+		gb.Install(version)
+		if !gb.existsVersion(version) {
+			// Install reported why
+			return
+		}
+	}
+	previous := gb.CurrentVersion()
+	// the pre-use hook may veto the switch
+	if err := gb.runHook(preUseHook, version, "GOBREW_FROM_VERSION="+previous, "GOBREW_TO_VERSION="+version); err != nil {
+		utils.ColorError.Fprintf(gb.stderr, "[Error] %s, not changing go version to: %s\n", err, version)
+		osExit(1)
 		return
 	}
-	utils.ColorInfo.Printf("[Info] Changing go version to: %s \n", version)
-	gb.changeSymblinkGoBin(version)
-	gb.changeSymblinkGo(version)
-	utils.ColorSuccess.Printf("[Success] Changed go version to: %s\n", version)
+	utils.ColorInfo.Fprintf(gb.stdout, "[Info] Changing go version to: %s \n", version)
+	if err := gb.switchCurrent(version); err != nil {
+		utils.ColorError.Fprintf(gb.stderr, "[Error] %s, not changing go version to: %s\n", err, version)
+		osExit(1)
+		return
+	}
+	utils.ColorSuccess.Fprintf(gb.stdout, "[Success] Changed go version to: %s\n", version)
+	if previous != "" {
+		if err := gb.setPreviousVersion(previous); err != nil {
+			gb.debug.Printf("recording the previous version: %s", err)
+		}
+	}
+	if env, err := gb.VersionEnv(version); err == nil && len(env) > 0 {
+		utils.ColorInfo.Fprintf(gb.stdout, "[Info] Version: %s has an env file, apply it with: eval \"$(gobrew shellenv)\"\n", version)
+	}
+	if gb.goToolchain != "" && os.Getenv("GOTOOLCHAIN") != gb.goToolchain && compareVersions(version, "1.21") >= 0 {
+		utils.ColorInfo.Fprintf(gb.stdout, "[Info] Set GOTOOLCHAIN=%s so go doesn't switch toolchains behind gobrew, with: eval \"$(gobrew shellenv)\"\n", gb.goToolchain)
+	}
+	if err := gb.ReinstallTools(version); err != nil {
+		utils.ColorError.Fprintf(gb.stderr, "[Error] %s\n", err)
+	}
 }
 
-func (gb *GoBrew) mkdirs(version string) {
-	os.MkdirAll(gb.installDir, os.ModePerm)
-	os.MkdirAll(gb.currentDir, os.ModePerm)
-	os.MkdirAll(gb.versionsDir, os.ModePerm)
-	os.MkdirAll(gb.getVersionDir(version), os.ModePerm)
-	os.MkdirAll(gb.downloadsDir, os.ModePerm)
+// mkdirs creates the directories installing version needs, failing on the
+// first one that can't be created, e.g. when the root isn't writable
+func (gb *GoBrew) mkdirs(version string) error {
+	for _, dir := range []string{gb.installDir, gb.currentDir, gb.versionsDir, gb.getVersionDir(version), gb.downloadsDir} {
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return fmt.Errorf("cannot create install directory: %w", err)
+		}
+	}
+	return nil
 }
 
+// getVersionDir of version in the user root, where it is installed to
 func (gb *GoBrew) getVersionDir(version string) string {
 	return filepath.Join(gb.versionsDir, version)
 }
-func (gb *GoBrew) downloadAndExtract(version string) {
-	tarName := "go" + version + "." + gb.getArch() + ".tar.gz"
-
-	downloadURL := registryPath + tarName
-	utils.ColorInfo.Printf("[Info] Downloading from: %s \n", downloadURL)
 
-	err := utils.Download(
-		downloadURL,
-		filepath.Join(gb.downloadsDir, tarName))
+// installedVersionDir of version, in the user root or else the system root
+func (gb *GoBrew) installedVersionDir(version string) string {
+	if gb.isSystemVersion(version) {
+		return filepath.Join(gb.systemVersionsDir, version)
+	}
+	return gb.getVersionDir(version)
+}
 
-	if err != nil {
-		gb.cleanVersionDir(version)
-		utils.ColorInfo.Printf("[Info]: Downloading version failed: %s \n", err)
-		utils.ColorError.Printf("[Error]: Please check connectivity to url: %s\n", downloadURL)
-		os.Exit(0)
+// isSystemVersion reports whether version is only installed in the system root
+func (gb *GoBrew) isSystemVersion(version string) bool {
+	if gb.systemVersionsDir == "" {
+		return false
 	}
+	if _, err := os.Stat(filepath.Join(gb.getVersionDir(version), gb.rootSubpath())); err == nil {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(gb.systemVersionsDir, version, gb.rootSubpath()))
+	return err == nil
+}
+
+// archiveName of the release archive of version for the host platform
+func (gb *GoBrew) archiveName(version string) string {
+	return archiveFile(version, gb.getArch())
+}
+
+// archiveFile is the release archive of version for platform, e.g. linux-amd64
+func archiveFile(version string, platform string) string {
+	return "go" + version + "." + platform + ".tar.gz"
+}
 
-	cmd := exec.Command(
-		"tar",
-		"-xf",
-		filepath.Join(gb.downloadsDir, tarName),
-		"-C",
-		gb.getVersionDir(version))
+// archiveURL the release archive of version is downloaded from
+func (gb *GoBrew) archiveURL(version string) string {
+	return gb.registryPath + gb.archiveName(version)
+}
+
+// DownloadURL returns the archive URL Install would download version from on
+// this platform, honoring GOBREW_REGISTRY, without touching the network or disk
+func (gb *GoBrew) DownloadURL(version string) (string, error) {
+	return gb.downloadURLFor(version, runtime.GOOS, runtime.GOARCH)
+}
 
-	utils.ColorInfo.Printf("[Success] Untar to %s\n", gb.getVersionDir(version))
-	_, err = cmd.Output()
+func (gb *GoBrew) downloadURLFor(version string, goos string, goarch string) (string, error) {
+	version, err := normalizeVersion(version)
 	if err != nil {
-		// clean up dir
-		gb.cleanVersionDir(version)
-		utils.ColorInfo.Printf("[Info]: Untar failed: %s \n", err)
-		utils.ColorError.Printf("[Error]: Please check if version exists from url: %s\n", downloadURL)
-		os.Exit(0)
+		return "", err
 	}
+	return gb.registryPath + archiveFile(version, platform(goos, goarch)), nil
 }
 
-func (gb *GoBrew) changeSymblinkGoBin(version string) {
+// downloadAndExtract installs the archive of version, from the downloads dir
+// when a valid one is cached. Failures are reported and cleaned up before
+// being returned
+func (gb *GoBrew) downloadAndExtract(version string) error {
+	tarName := gb.archiveName(version)
 
-	goBinDst := filepath.Join(gb.versionsDir, version, "/go/bin")
-	os.RemoveAll(gb.currentBinDir)
+	downloadURL := gb.archiveURL(version)
+	archive := filepath.Join(gb.downloadsDir, tarName)
 
-	cmd := exec.Command("ln", "-snf", goBinDst, gb.currentBinDir)
+	var err, extractErr error
+	if gb.cachedArchiveValid(archive) {
+		utils.ColorInfo.Fprintf(gb.stdout, "[Info] Using cached archive: %s \n", archive)
+		gb.reportProgress(ProgressEvent{Stage: StageExtracting})
+		extractErr = gb.extractVersion(archive, version)
+	} else {
+		utils.ColorInfo.Fprintf(gb.stdout, "[Info] Downloading from: %s \n", downloadURL)
+		gb.debug.Printf("downloading %s to %s", downloadURL, gb.downloadsDir)
+		published, publishedErr := gb.publishedFile(tarName)
+		if publishedErr != nil {
+			gb.debug.Printf("not verifying %s: no published checksum: %s", tarName, publishedErr)
+		}
+		stop := gb.reportDownload(archive, published.Size)
+		// the archive is hashed as it is extracted, a mismatch tries the
+		// next mirror and any other failure is an untar failure
+		err = gb.fetchVerified(version, archive, func() error {
+			stop()
+			gb.reportProgress(ProgressEvent{Stage: StageExtracting})
+			extractErr = gb.extractVerified(archive, version, published.SHA256)
+			if errors.Is(extractErr, ErrChecksumMismatch) {
+				stop = gb.reportDownload(archive, published.Size)
+				return extractErr
+			}
+			return nil
+		})
+		stop()
+	}
 
-	_, err := cmd.Output()
 	if err != nil {
-		utils.ColorError.Printf("[Error]: symbolic link failed: %s\n", err)
-		os.Exit(0)
+		gb.cleanVersionDir(version)
+		utils.ColorInfo.Fprintf(gb.stdout, "[Info]: Downloading version failed: %s \n", err)
+		utils.ColorError.Fprintf(gb.stderr, "[Error]: Please check connectivity to url: %s\n", downloadURL)
+		return err
 	}
 
+	if extractErr != nil {
+		// clean up dir
+		gb.cleanVersionDir(version)
+		utils.ColorInfo.Fprintf(gb.stdout, "[Info]: Untar failed: %s \n", extractErr)
+		if err := gb.explainMissingBuild(version); err != nil {
+			utils.ColorError.Fprintf(gb.stderr, "[Error]: %s\n", err)
+			return err
+		}
+		utils.ColorError.Fprintf(gb.stderr, "[Error]: Please check if version exists from url: %s\n", downloadURL)
+		return extractErr
+	}
+	utils.ColorSuccess.Fprintf(gb.stdout, "[Success] Untar to %s\n", gb.getVersionDir(version))
+	gb.reportProgress(ProgressEvent{Stage: StageVerifying})
+	if err := gb.checkReportedVersion(version); err != nil {
+		gb.cleanVersionDir(version)
+		utils.ColorError.Fprintf(gb.stderr, "[Error]: %s, downloaded from url: %s\n", err, downloadURL)
+		return err
+	}
+	return nil
 }
-func (gb *GoBrew) changeSymblinkGo(version string) {
 
-	os.RemoveAll(gb.currentGoDir)
-	versionGoDir := filepath.Join(gb.versionsDir, gb.CurrentVersion(), "go")
-	cmd := exec.Command("ln", "-snf", versionGoDir, gb.currentGoDir)
+// switchCurrent links version as the current version, holding currentLock so
+// both links always agree
+func (gb *GoBrew) switchCurrent(version string) error {
+	return gb.withCurrentLock(func() error {
+		gb.changeSymblinkGoBin(version)
+		gb.changeSymblinkGo(version)
+		gb.clearSystemSelected()
+		return nil
+	})
+}
 
-	_, err := cmd.Output()
-	if err != nil {
-		utils.ColorError.Printf("[Error]: symbolic link failed: %s\n", err)
+func (gb *GoBrew) changeSymblinkGoBin(version string) {
+	if err := replaceLink(gb.versionBinDir(version), gb.currentBinDir); err != nil {
+		utils.ColorError.Fprintf(gb.stderr, "[Error]: symbolic link failed: %s\n", err)
+		os.Exit(0)
+	}
+}
+func (gb *GoBrew) changeSymblinkGo(version string) {
+	if err := replaceLink(gb.versionGoroot(version), gb.currentGoDir); err != nil {
+		utils.ColorError.Fprintf(gb.stderr, "[Error]: symbolic link failed: %s\n", err)
 		os.Exit(0)
 	}
 }
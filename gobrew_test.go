@@ -1,7 +1,23 @@
 package gobrew
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 )
 
 func Test_Todo(t *testing.T) {
@@ -14,3 +30,700 @@ func Test_Todo(t *testing.T) {
 
 	// write tests
 }
+
+// newTestGoBrew lays out a GoBrew rooted at root, the same way NewGoBrew does
+// for GOBREW_ROOT.
+func newTestGoBrew(root string) GoBrew {
+	return GoBrew{
+		homeDir:       root,
+		installDir:    root,
+		versionsDir:   filepath.Join(root, "versions"),
+		currentDir:    filepath.Join(root, "current"),
+		currentBinDir: filepath.Join(root, "current", "bin"),
+		currentGoDir:  filepath.Join(root, "current", "go"),
+		downloadsDir:  filepath.Join(root, "downloads"),
+
+		stripComponents: -1,
+		binSubpath:      defaultBinSubpath,
+		extractWorkers:  1,
+		gitTimeout:      defaultGitTimeout,
+		registryPath:    defaultRegistryPath,
+		tagsRepo:        defaultTagsRepo,
+		// no JSON API unless a test serves one, see serveReleases
+		releasesURL:      "",
+		releasesCacheTTL: defaultReleasesCacheTTL,
+		downloadMaxAge:   defaultDownloadMaxAge,
+		goToolchain:      defaultGoToolchain,
+		stdout:           ioutil.Discard,
+		stderr:           ioutil.Discard,
+		debug:            log.New(ioutil.Discard, "", 0),
+	}
+}
+
+// tempDir creates a temporary directory removed at the end of the test.
+func tempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "gobrew-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+// fakeBin puts an executable shell script called name first on PATH for the
+// rest of the test
+func fakeBin(t *testing.T, name string, script string) {
+	t.Helper()
+	dir := tempDir(t)
+	if err := ioutil.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := os.Getenv("PATH")
+	os.Setenv("PATH", dir+string(os.PathListSeparator)+path)
+	t.Cleanup(func() { os.Setenv("PATH", path) })
+}
+
+// writeTarGz writes a gzipped tarball at path holding files (name -> content).
+func writeTarGz(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+	for name, content := range files {
+		hdr := &tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestListVersionsFormat(t *testing.T) {
+	tmpl, err := ParseListFormat("{{.Version}} {{.Current}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	infos := []VersionInfo{
+		{Version: "1.16", Current: false},
+		{Version: "1.17.6", Current: true},
+		{Version: "1.18beta1", Current: false},
+	}
+	var buf bytes.Buffer
+	if err := renderVersions(&buf, tmpl, infos); err != nil {
+		t.Fatal(err)
+	}
+	want := "1.16 false\n1.17.6 true\n1.18beta1 false\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestRenderVersionTable(t *testing.T) {
+	installed := time.Date(2021, 12, 10, 9, 30, 0, 0, time.UTC)
+	infos := []VersionInfo{
+		{Version: "1.9", Size: 512, InstalledAt: installed},
+		{Version: "1.17.6", Current: true, Size: 350 * 1024 * 1024, InstalledAt: installed},
+	}
+	var buf bytes.Buffer
+	if err := renderVersionTable(&buf, infos); err != nil {
+		t.Fatal(err)
+	}
+	want := "" +
+		"VERSION  CURRENT  SIZE      INSTALLED\n" +
+		"1.9               512 B     2021-12-10 09:30\n" +
+		"1.17.6   *        350.0 MB  2021-12-10 09:30\n"
+	if buf.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestParseListFormatInvalid(t *testing.T) {
+	if _, err := ParseListFormat("{{.Version"); err == nil {
+		t.Error("expected an error for an unterminated template")
+	}
+}
+
+func TestVersionStatus(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	for _, v := range []string{"1.9", "1.18beta1", "1.16.3", "1.10"} {
+		os.MkdirAll(filepath.Join(gb.getVersionDir(v), "go", "bin"), os.ModePerm)
+	}
+	os.MkdirAll(gb.currentDir, os.ModePerm)
+	os.Symlink(filepath.Join(gb.getVersionDir("1.16.3"), "go", "bin"), gb.currentBinDir)
+
+	infos, err := gb.VersionStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []VersionInfo{
+		{Version: "1.9", dir: gb.getVersionDir("1.9")},
+		{Version: "1.10", dir: gb.getVersionDir("1.10")},
+		{Version: "1.16.3", Current: true, dir: gb.getVersionDir("1.16.3")},
+		{Version: "1.18beta1", dir: gb.getVersionDir("1.18beta1")},
+	}
+	if !reflect.DeepEqual(infos, want) {
+		t.Errorf("got %v, want %v", infos, want)
+	}
+}
+
+func TestRemoteVersionsTimeout(t *testing.T) {
+	fakeBin(t, "git", "exec sleep 5\n")
+	gb := newTestGoBrew(tempDir(t))
+	gb.gitTimeout = 100 * time.Millisecond
+
+	start := time.Now()
+	_, err := gb.RemoteVersions()
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("expected ErrTimeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("timeout did not fire in time, took %s", elapsed)
+	}
+}
+
+func TestRemoteVersionsFakeGit(t *testing.T) {
+	fakeBin(t, "git", "printf 'abc\\trefs/tags/go1.16\\ndef\\trefs/tags/go1.17\\n'\n")
+	gb := newTestGoBrew(tempDir(t))
+
+	versions, err := gb.RemoteVersions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(versions, []string{"1.16", "1.17"}) {
+		t.Errorf("unexpected versions %v", versions)
+	}
+}
+
+func TestRemoteVersionsAPIDown(t *testing.T) {
+	fakeBin(t, "git", "printf 'abc\\trefs/tags/go1.16\\ndef\\trefs/tags/go1.17\\n'\n")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	gb := newTestGoBrew(tempDir(t))
+	gb.releasesURL = server.URL + "/?mode=json&include=all"
+
+	versions, err := gb.RemoteVersions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(versions, []string{"1.16", "1.17"}) {
+		t.Errorf("expected the git tags, got %v", versions)
+	}
+}
+
+func TestRemoteVersionsMerged(t *testing.T) {
+	fakeBin(t, "git", "printf 'abc\\trefs/tags/go1\\ndef\\trefs/tags/go1.16\\n'\n")
+	gb := newTestGoBrew(tempDir(t))
+	serveReleases(t, &gb, hostReleases("1.17", "1.16"))
+
+	versions, err := gb.RemoteVersions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(versions, []string{"1", "1.16", "1.17"}) {
+		t.Errorf("expected the tags and releases once each, got %v", versions)
+	}
+
+	fakeBin(t, "git", "exit 128\n")
+	if versions, err := gb.RemoteVersions(); err != nil || !reflect.DeepEqual(versions, []string{"1.16", "1.17"}) {
+		t.Errorf("expected the JSON API releases when git fails, got (%v, %v)", versions, err)
+	}
+	gb.releasesURL = ""
+	if _, err := gb.RemoteVersions(); err == nil {
+		t.Error("expected an error when both the JSON API and git fail")
+	}
+}
+
+// serveArchives starts a registry serving a release archive for each version
+// with a go binary reporting that version
+func serveArchives(t *testing.T, gb GoBrew, versions ...string) *httptest.Server {
+	t.Helper()
+	dir := tempDir(t)
+	for _, version := range versions {
+		writeTarGz(t, filepath.Join(dir, "go"+version+"."+gb.getArch()+".tar.gz"), map[string]string{
+			"go/bin/go":    "#!/bin/sh\necho 'go version go" + version + " " + strings.Replace(gb.getArch(), "-", "/", 1) + "'\n",
+			"go/bin/gofmt": "#!/bin/sh\n",
+		})
+	}
+	server := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestUseAutoInstall(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	gb.registryPath = serveArchives(t, gb, "1.16").URL + "/"
+	gb.autoInstall = true
+
+	gb.Use("1.16")
+
+	if !gb.existsVersion("1.16") {
+		t.Fatal("expected 1.16 to be installed")
+	}
+	if cv := gb.CurrentVersion(); cv != "1.16" {
+		t.Errorf("expected current version 1.16, got %q", cv)
+	}
+}
+
+func TestEnableAutoInstall(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	gb.registryPath = serveArchives(t, gb, "1.16").URL + "/"
+	gb.EnableAutoInstall()
+
+	gb.Use("1.16")
+
+	if cv := gb.CurrentVersion(); cv != "1.16" {
+		t.Errorf("expected 1.16 installed and current, got %q", cv)
+	}
+}
+
+func TestUseAutoInstallFailure(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	gb.registryPath = serveArchives(t, gb).URL + "/"
+	gb.EnableAutoInstall()
+	exited := false
+	osExit = func(code int) { exited = true }
+	defer func() { osExit = os.Exit }()
+
+	gb.Use("1.16")
+
+	if !exited {
+		t.Error("expected the failed install reported")
+	}
+	if _, err := os.Lstat(gb.currentBinDir); !os.IsNotExist(err) {
+		t.Errorf("expected no switch to a version that failed to install, got %v", err)
+	}
+}
+
+func TestListVersionsFreshRoot(t *testing.T) {
+	gb := newTestGoBrew(filepath.Join(tempDir(t), "fresh"))
+	var buf bytes.Buffer
+	gb.stdout = &buf
+
+	versions, err := gb.InstalledVersions()
+	if err != nil || len(versions) != 0 {
+		t.Errorf("expected no versions and no error, got (%v, %v)", versions, err)
+	}
+	gb.ListVersions()
+	if !strings.Contains(buf.String(), "No versions installed") {
+		t.Errorf("expected a friendly message, got %q", buf.String())
+	}
+}
+
+func TestListVersionsHasNoTimestamps(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	for _, v := range []string{"1.16", "1.17"} {
+		os.MkdirAll(filepath.Join(gb.getVersionDir(v), "go", "bin"), os.ModePerm)
+	}
+	os.MkdirAll(gb.currentDir, os.ModePerm)
+	os.Symlink(filepath.Join(gb.getVersionDir("1.17"), "go", "bin"), gb.currentBinDir)
+	var buf bytes.Buffer
+	gb.stdout = &buf
+
+	gb.ListVersions()
+
+	want := "1.16\n1.17*\n\ncurrent: 1.17\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+	timestamp := regexp.MustCompile(`^\d{4}/\d{2}/\d{2} `)
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if timestamp.MatchString(line) {
+			t.Errorf("line %q has a timestamp prefix", line)
+		}
+	}
+}
+
+func TestDownloadDirOverride(t *testing.T) {
+	root := tempDir(t)
+	cache := filepath.Join(tempDir(t), "cache")
+	os.Setenv("GOBREW_ROOT", root)
+	os.Setenv("GOBREW_DOWNLOAD_DIR", cache)
+	defer os.Unsetenv("GOBREW_ROOT")
+	defer os.Unsetenv("GOBREW_DOWNLOAD_DIR")
+
+	gb, err := NewGoBrew()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gb.downloadsDir != cache {
+		t.Fatalf("expected downloads in %s, got %s", cache, gb.downloadsDir)
+	}
+	gb.stdout = ioutil.Discard
+	gb.registryPath = serveArchives(t, gb, "1.16").URL + "/"
+
+	if err := gb.mkdirs("1.16"); err != nil {
+		t.Fatal(err)
+	}
+	ioutil.WriteFile(filepath.Join(cache, "unrelated"), nil, 0644)
+	gb.downloadAndExtract("1.16")
+
+	if _, err := os.Stat(filepath.Join(cache, "go1.16."+gb.getArch()+".tar.gz")); err != nil {
+		t.Errorf("expected the archive in the download dir: %s", err)
+	}
+	if !gb.existsVersion("1.16") || !strings.HasPrefix(gb.getVersionDir("1.16"), root) {
+		t.Errorf("expected 1.16 extracted under %s", root)
+	}
+
+	gb.cleanDownloadsDir()
+	if _, err := os.Stat(filepath.Join(cache, "unrelated")); err != nil {
+		t.Errorf("cleaning must leave other files in a shared download dir: %s", err)
+	}
+}
+
+func TestRootExpansion(t *testing.T) {
+	home := tempDir(t)
+	defer os.Setenv("HOME", os.Getenv("HOME"))
+	os.Setenv("HOME", home)
+	defer os.Unsetenv("GOBREW_ROOT")
+
+	for root, want := range map[string]string{
+		"$HOME/go-toolchains":   filepath.Join(home, "go-toolchains"),
+		"${HOME}/go-toolchains": filepath.Join(home, "go-toolchains"),
+		"~/go-toolchains":       filepath.Join(home, "go-toolchains"),
+		"~":                     home,
+		"go-toolchains":         filepath.Join(home, goBrewDir),
+	} {
+		os.Setenv("GOBREW_ROOT", root)
+		gb, err := NewGoBrew()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gb.installDir != want {
+			t.Errorf("GOBREW_ROOT=%s: expected %s, got %s", root, want, gb.installDir)
+		}
+		if gb.versionsDir != filepath.Join(want, "versions") {
+			t.Errorf("GOBREW_ROOT=%s: expected versions in %s, got %s", root, want, gb.versionsDir)
+		}
+	}
+}
+
+func TestNewGoBrewErrors(t *testing.T) {
+	defer os.Setenv("HOME", os.Getenv("HOME"))
+	defer os.Unsetenv("GOBREW_ROOT")
+
+	os.Setenv("HOME", "")
+	os.Unsetenv("GOBREW_ROOT")
+	if _, err := NewGoBrew(); err == nil {
+		t.Error("expected an error without a home dir")
+	}
+	os.Setenv("GOBREW_ROOT", tempDir(t))
+	if _, err := NewGoBrew(); err != nil {
+		t.Errorf("GOBREW_ROOT should do without a home dir: %s", err)
+	}
+
+	file := filepath.Join(tempDir(t), "root")
+	ioutil.WriteFile(file, nil, 0644)
+	os.Setenv("GOBREW_ROOT", file)
+	if _, err := NewGoBrew(); err == nil {
+		t.Error("expected an error when the root is a file")
+	}
+}
+
+func TestNewGoBrewIndependent(t *testing.T) {
+	defer os.Unsetenv("GOBREW_ROOT")
+	defer os.Unsetenv("GOBREW_DOWNLOAD_CACHE_MAX")
+
+	first, second := tempDir(t), tempDir(t)
+	os.Setenv("GOBREW_ROOT", first)
+	os.Setenv("GOBREW_DOWNLOAD_CACHE_MAX", "100")
+	a, err := NewGoBrew()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv("GOBREW_ROOT", second)
+	os.Unsetenv("GOBREW_DOWNLOAD_CACHE_MAX")
+	b, err := NewGoBrew()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if a.installDir != first || a.versionsDir != filepath.Join(first, "versions") || a.downloadCacheMax != 100 {
+		t.Errorf("first instance changed: %s, %s, %d", a.installDir, a.versionsDir, a.downloadCacheMax)
+	}
+	if b.installDir != second || b.versionsDir != filepath.Join(second, "versions") {
+		t.Errorf("second instance has the wrong root: %s, %s", b.installDir, b.versionsDir)
+	}
+	if b.downloadCacheMax != 0 {
+		t.Errorf("second instance inherited GOBREW_DOWNLOAD_CACHE_MAX from the first: %d", b.downloadCacheMax)
+	}
+}
+
+func TestRemoteVersionsWithoutGitSort(t *testing.T) {
+	fakeBin(t, "git", `for arg in "$@"; do
+	case "$arg" in --sort*) echo "error: unknown option '$arg'" >&2; exit 129;; esac
+done
+printf 'a\trefs/tags/go1.10\nb\trefs/tags/go1.9.2\nc\trefs/tags/go1.10rc1\nd\trefs/tags/go1.9\n'
+`)
+	gb := newTestGoBrew(tempDir(t))
+
+	versions, err := gb.RemoteVersions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"1.9", "1.9.2", "1.10rc1", "1.10"}
+	if !reflect.DeepEqual(versions, want) {
+		t.Errorf("got %v, want %v", versions, want)
+	}
+}
+
+func TestWalkInstalledStopsEarly(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	for _, v := range []string{"1.15", "1.16", "1.17"} {
+		os.MkdirAll(filepath.Join(gb.getVersionDir(v), "go", "bin"), os.ModePerm)
+		ioutil.WriteFile(filepath.Join(gb.getVersionDir(v), "go", "bin", "go"), make([]byte, 100), 0755)
+	}
+
+	visited := make([]string, 0)
+	err := gb.WalkInstalled(func(info VersionInfo) error {
+		visited = append(visited, info.Version)
+		size, err := info.DiskSize()
+		if err != nil || size != 100 {
+			t.Errorf("unexpected size %d for %s: %v", size, info.Version, err)
+		}
+		if info.Version == "1.16" {
+			return ErrStopWalk
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(visited, []string{"1.15", "1.16"}) {
+		t.Errorf("expected the walk to stop after 1.16, visited %v", visited)
+	}
+
+	boom := errors.New("boom")
+	if err := gb.WalkInstalled(func(VersionInfo) error { return boom }); err != boom {
+		t.Errorf("expected the callback error passed on, got %v", err)
+	}
+}
+
+func TestPrereleaseArchiveURL(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	for version, want := range map[string]string{
+		"1.22rc1":   "https://go.dev/dl/go1.22rc1." + gb.getArch() + ".tar.gz",
+		"1.22beta1": "https://go.dev/dl/go1.22beta1." + gb.getArch() + ".tar.gz",
+	} {
+		if got := gb.archiveURL(version); got != want {
+			t.Errorf("archiveURL(%s) = %s, want %s", version, got, want)
+		}
+	}
+}
+
+func TestDownloadURL(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	gb.registryPath = "https://mirror.example.com/golang/"
+	tests := []struct {
+		version, goos, goarch string
+		want                  string
+	}{
+		{version: "1.21.3", goos: "linux", goarch: "amd64", want: "https://mirror.example.com/golang/go1.21.3.linux-amd64.tar.gz"},
+		{version: "go1.21.3", goos: "darwin", goarch: "arm64", want: "https://mirror.example.com/golang/go1.21.3.darwin-arm64.tar.gz"},
+		{version: "1.22-rc1", goos: "linux", goarch: "arm64", want: "https://mirror.example.com/golang/go1.22rc1.linux-arm64.tar.gz"},
+		{version: "1.20beta1", goos: "freebsd", goarch: "386", want: "https://mirror.example.com/golang/go1.20beta1.freebsd-386.tar.gz"},
+		{version: "1.21.3", goos: "linux", goarch: "arm", want: "https://mirror.example.com/golang/go1.21.3.linux-armv6l.tar.gz"},
+	}
+	for _, tt := range tests {
+		got, err := gb.downloadURLFor(tt.version, tt.goos, tt.goarch)
+		if err != nil {
+			t.Fatalf("%s %s/%s: %s", tt.version, tt.goos, tt.goarch, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s %s/%s: got %s, want %s", tt.version, tt.goos, tt.goarch, got, tt.want)
+		}
+	}
+
+	if got, err := gb.DownloadURL("1.21.3"); err != nil || got != gb.archiveURL("1.21.3") {
+		t.Errorf("expected the url Install uses, got %s, %v", got, err)
+	}
+	if _, err := gb.DownloadURL("latest-ish"); err == nil {
+		t.Error("expected an error for an invalid version")
+	}
+}
+
+func TestInstallPrerelease(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	gb.registryPath = serveArchives(t, gb, "1.22rc1", "1.22beta1").URL + "/"
+
+	gb.Install("go1.22rc1")
+	gb.Install("1.22-beta1")
+
+	for _, version := range []string{"1.22rc1", "1.22beta1"} {
+		if err := gb.Verify(version); err != nil {
+			t.Errorf("expected %s installed: %s", version, err)
+		}
+	}
+}
+
+func TestMkdirsReadOnlyRoot(t *testing.T) {
+	if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+		t.Skip("permissions are not enforced")
+	}
+	parent := tempDir(t)
+	os.Chmod(parent, 0555)
+	defer os.Chmod(parent, 0755)
+	gb := newTestGoBrew(filepath.Join(parent, ".gobrew"))
+
+	err := gb.mkdirs("1.16")
+	if err == nil || !strings.Contains(err.Error(), "cannot create install directory") || !os.IsPermission(errors.Unwrap(err)) {
+		t.Errorf("expected a permission denied error, got %v", err)
+	}
+}
+
+func TestIsInstalled(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	installSizedVersion(t, gb, "1.17.6", 10)
+	installSizedVersion(t, gb, "1.22rc1", 10)
+	if err := gb.Alias("stable", "1.17.6"); err != nil {
+		t.Fatal(err)
+	}
+
+	for version, want := range map[string]bool{
+		"1.17.6":    true,
+		"go1.17.6":  true,
+		"v1.17.6":   true,
+		"1.22rc1":   true,
+		"1.22-rc1":  true,
+		"go1.22rc1": true,
+		"stable":    true,
+		"1.17":      false,
+		"1.22":      false,
+		"":          false,
+	} {
+		if got := gb.IsInstalled(version); got != want {
+			t.Errorf("IsInstalled(%q) = %v, want %v", version, got, want)
+		}
+	}
+}
+
+func TestDownloadFollowsRedirects(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	mirror := serveArchives(t, gb, "1.16")
+	var redirected int
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected++
+		http.Redirect(w, r, mirror.URL+"/"+path.Base(r.URL.Path), http.StatusFound)
+	}))
+	defer registry.Close()
+	gb.registryPath = registry.URL + "/dl/"
+
+	if err := gb.mkdirs("1.16"); err != nil {
+		t.Fatal(err)
+	}
+	gb.downloadAndExtract("1.16")
+
+	if redirected != 1 {
+		t.Errorf("expected the download redirected once, got %d", redirected)
+	}
+	if err := gb.Verify("1.16"); err != nil {
+		t.Errorf("expected 1.16 installed from the redirect target: %s", err)
+	}
+}
+
+func TestGroupByMinor(t *testing.T) {
+	groups := GroupByMinor([]string{"1.20.1", "1.19.5", "1.20", "1.19.1", "1.20rc1", "1.19.10", "external-tip"})
+	want := map[string][]string{
+		"1.19":  {"1.19.1", "1.19.5", "1.19.10"},
+		"1.20":  {"1.20rc1", "1.20", "1.20.1"},
+		"other": {"external-tip"},
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("got %v, want %v", groups, want)
+	}
+}
+
+func TestListGrouped(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	for _, version := range []string{"1.9.2", "1.10", "1.10.1", "1.9"} {
+		installSizedVersion(t, gb, version, 10)
+	}
+	useFixture(t, gb, "1.10")
+	var buf bytes.Buffer
+	gb.stdout = &buf
+
+	if err := gb.ListGrouped(); err != nil {
+		t.Fatal(err)
+	}
+	if want := "1.9\t1.9  1.9.2  \n1.10\t1.10*  1.10.1  \n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestListPlain(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	for _, version := range []string{"1.9.2", "1.10", "1.10rc1", "1.9"} {
+		installSizedVersion(t, gb, version, 10)
+	}
+	useFixture(t, gb, "1.10")
+	var buf bytes.Buffer
+	gb.stdout = &buf
+
+	if err := gb.ListPlain(); err != nil {
+		t.Fatal(err)
+	}
+	if want := "1.9\n1.9.2\n1.10rc1\n1.10\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestUntarSuccessOnlyLoggedOnSuccess(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	var stdout bytes.Buffer
+	gb.stdout = &stdout
+	gb.registryPath = serveArchives(t, gb, "1.16").URL + "/"
+
+	gb.Install("1.16")
+	if !strings.Contains(stdout.String(), "[Success] Untar to "+gb.getVersionDir("1.16")) {
+		t.Errorf("expected the untar success logged, got %q", stdout.String())
+	}
+
+	// an archive that isn't one fails to untar
+	dir := tempDir(t)
+	if err := ioutil.WriteFile(filepath.Join(dir, "go1.17."+gb.getArch()+".tar.gz"), []byte("not an archive"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	registry := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	defer registry.Close()
+	gb.registryPath = registry.URL + "/"
+	exited := false
+	osExit = func(code int) { exited = true }
+	defer func() { osExit = os.Exit }()
+	stdout.Reset()
+
+	gb.Install("1.17")
+	if !exited || gb.existsVersion("1.17") {
+		t.Fatal("expected the install of 1.17 to fail")
+	}
+	if !strings.Contains(stdout.String(), "Untar failed") {
+		t.Errorf("expected the untar failure logged, got %q", stdout.String())
+	}
+	if strings.Contains(stdout.String(), "Untar to") {
+		t.Errorf("expected no untar success logged for a failed untar, got %q", stdout.String())
+	}
+}
+
+func TestParseRemoteTags(t *testing.T) {
+	raw := "abc\trefs/tags/go1.16\ndef\trefs/tags/go1.17rc1\n"
+	versions := parseRemoteTags(raw)
+	if len(versions) != 2 || versions[0] != "1.16" || versions[1] != "1.17rc1" {
+		t.Errorf("unexpected versions %v", versions)
+	}
+}
@@ -0,0 +1,45 @@
+package gobrew
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/kevincobain2000/gobrew/utils"
+)
+
+const (
+	hooksDir        = "hooks"
+	postInstallHook = "post-install"
+	preUseHook      = "pre-use"
+)
+
+// runHook runs the executable <root>/hooks/<name>, if any, for version with
+// GOBREW_VERSION and GOROOT set and its go first on PATH, plus env
+func (gb *GoBrew) runHook(name string, version string, env ...string) error {
+	hook := filepath.Join(gb.installDir, hooksDir, name)
+	if _, err := os.Stat(hook); os.IsNotExist(err) {
+		return nil
+	}
+	versionEnv, err := gb.ExecEnv(version)
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(hook)
+	cmd.Env = mergeEnv(versionEnv, append([]string{"GOBREW_VERSION=" + version}, env...)...)
+	cmd.Stdout = gb.stdout
+	cmd.Stderr = gb.stderr
+	gb.debug.Printf("running %s hook for %s", name, version)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s hook: %s", name, err)
+	}
+	return nil
+}
+
+// runPostInstallHook reports a failing hook, the install itself succeeded
+func (gb *GoBrew) runPostInstallHook(version string) {
+	if err := gb.runHook(postInstallHook, version); err != nil {
+		utils.ColorError.Fprintf(gb.stderr, "[Error] %s, version %s is installed regardless\n", err, version)
+	}
+}
@@ -0,0 +1,93 @@
+//go:build !windows
+// +build !windows
+
+package gobrew
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeHook installs script as the hook called name
+func writeHook(t *testing.T, gb GoBrew, name string, script string) {
+	t.Helper()
+	os.MkdirAll(filepath.Join(gb.installDir, hooksDir), os.ModePerm)
+	if err := ioutil.WriteFile(filepath.Join(gb.installDir, hooksDir, name), []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPostInstallHook(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	archive := filepath.Join(tempDir(t), "go.tar.gz")
+	writeTarGz(t, archive, map[string]string{"go/bin/go": "go"})
+	log := filepath.Join(tempDir(t), "hook.log")
+	writeHook(t, gb, postInstallHook, `echo "$GOBREW_VERSION $GOROOT ${PATH%%:*}" > `+log+"\n")
+
+	if err := gb.InstallFromFile("1.16", archive, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := ioutil.ReadFile(log)
+	if err != nil {
+		t.Fatalf("expected the hook to run: %s", err)
+	}
+	goroot := filepath.Join(gb.getVersionDir("1.16"), "go")
+	if want := "1.16 " + goroot + " " + filepath.Join(goroot, "bin") + "\n"; string(content) != want {
+		t.Errorf("got %q, want %q", content, want)
+	}
+}
+
+func TestPostInstallHookFailure(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	var stderr bytes.Buffer
+	gb.stderr = &stderr
+	archive := filepath.Join(tempDir(t), "go.tar.gz")
+	writeTarGz(t, archive, map[string]string{"go/bin/go": "go"})
+	writeHook(t, gb, postInstallHook, "exit 3\n")
+
+	if err := gb.InstallFromFile("1.16", archive, ""); err != nil {
+		t.Fatalf("a failing hook must not fail the install: %s", err)
+	}
+	if !gb.existsVersion("1.16") {
+		t.Error("expected 1.16 installed")
+	}
+	if !strings.Contains(stderr.String(), "post-install hook: exit status 3") {
+		t.Errorf("expected the hook exit status reported, got %q", stderr.String())
+	}
+}
+
+func TestPreUseHook(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	installFakeVersion(t, gb, "1.16", "go version go1.16 linux/amd64")
+	installFakeVersion(t, gb, "1.17", "go version go1.17 linux/amd64")
+	useFixture(t, gb, "1.16")
+	log := filepath.Join(tempDir(t), "hook.log")
+	writeHook(t, gb, preUseHook, `echo "$GOBREW_FROM_VERSION -> $GOBREW_TO_VERSION" > `+log+"\nexit 1\n")
+
+	var exitCode int
+	osExit = func(code int) { exitCode = code }
+	defer func() { osExit = os.Exit }()
+	gb.Use("1.17")
+
+	if exitCode != 1 {
+		t.Errorf("expected the use aborted with status 1, got %d", exitCode)
+	}
+	if cv := gb.CurrentVersion(); cv != "1.16" {
+		t.Errorf("expected 1.16 still current, got %s", cv)
+	}
+	content, _ := ioutil.ReadFile(log)
+	if string(content) != "1.16 -> 1.17\n" {
+		t.Errorf("expected the hook to get the from and to versions, got %q", content)
+	}
+
+	writeHook(t, gb, preUseHook, "exit 0\n")
+	gb.Use("1.17")
+	if cv := gb.CurrentVersion(); cv != "1.17" {
+		t.Errorf("expected 1.17 current once the hook passes, got %s", cv)
+	}
+}
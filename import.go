@@ -0,0 +1,77 @@
+package gobrew
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/kevincobain2000/gobrew/utils"
+)
+
+// ImportFromGoenv makes the versions of a goenv install gobrew managed. Each
+// GOROOT in $GOENV_ROOT/versions (~/.goenv by default) is linked as
+// versionsDir/<version>/go, the same way UseExternal registers a GOROOT, so
+// uninstalling it from gobrew leaves goenv untouched
+func (gb *GoBrew) ImportFromGoenv() error {
+	root := os.Getenv("GOENV_ROOT")
+	if root == "" {
+		root = filepath.Join(gb.homeDir, ".goenv")
+	}
+	return gb.importVersions("goenv", filepath.Join(root, "versions"))
+}
+
+// ImportFromGvm makes the versions of a gvm install gobrew managed, from
+// $GVM_ROOT/gos (~/.gvm by default) where they are named like go1.21.3
+func (gb *GoBrew) ImportFromGvm() error {
+	root := os.Getenv("GVM_ROOT")
+	if root == "" {
+		root = filepath.Join(gb.homeDir, ".gvm")
+	}
+	return gb.importVersions("gvm", filepath.Join(root, "gos"))
+}
+
+// importVersions links every GOROOT directly under dir named after a go
+// release, skipping versions gobrew already has
+func (gb *GoBrew) importVersions(manager string, dir string) error {
+	entries, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("no %s install found: %s does not exist", manager, dir)
+	}
+	if err != nil {
+		return err
+	}
+
+	imported := 0
+	for _, entry := range entries {
+		goroot := filepath.Join(dir, entry.Name())
+		if _, err := os.Stat(filepath.Join(goroot, "bin", exeName("go"))); err != nil {
+			gb.debug.Printf("skipping %s: %s", goroot, err)
+			continue
+		}
+		version, err := normalizeVersion(entry.Name())
+		if err != nil {
+			utils.ColorInfo.Fprintf(gb.stdout, "[Info] Skipping %s: %s\n", goroot, err)
+			continue
+		}
+		if gb.existsVersion(version) {
+			utils.ColorInfo.Fprintf(gb.stdout, "[Info] Version: %s exists \n", version)
+			continue
+		}
+		if err := os.MkdirAll(gb.getVersionDir(version), os.ModePerm); err != nil {
+			return err
+		}
+		link := filepath.Join(gb.getVersionDir(version), "go")
+		// a link left dangling by a previous import
+		os.Remove(link)
+		if err := os.Symlink(goroot, link); err != nil {
+			return err
+		}
+		utils.ColorSuccess.Fprintf(gb.stdout, "[Success] Imported version: %s from %s\n", version, manager)
+		imported++
+	}
+	if imported == 0 {
+		utils.ColorInfo.Fprintf(gb.stdout, "[Info] No new versions found in %s\n", dir)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package gobrew
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// writeGoroot lays out a GOROOT like another version manager would, its go
+// printing goVersionOutput
+func writeGoroot(t *testing.T, goroot string, goVersionOutput string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(goroot, "bin"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	scripts := map[string]string{
+		"go":    "#!/bin/sh\necho '" + goVersionOutput + "'\n",
+		"gofmt": "#!/bin/sh\n",
+	}
+	for name, script := range scripts {
+		if err := ioutil.WriteFile(filepath.Join(goroot, "bin", name), []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestImportFromGoenv(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	goenvRoot := tempDir(t)
+	os.Setenv("GOENV_ROOT", goenvRoot)
+	defer os.Unsetenv("GOENV_ROOT")
+	versions := filepath.Join(goenvRoot, "versions")
+	writeGoroot(t, filepath.Join(versions, "1.20.5"), "go version go1.20.5 linux/amd64")
+	writeGoroot(t, filepath.Join(versions, "1.21.0"), "go version go1.21.0 linux/amd64")
+	os.MkdirAll(filepath.Join(versions, "broken"), os.ModePerm)
+	installFakeVersion(t, gb, "1.21.0", "go version go1.21.0 linux/amd64")
+
+	if err := gb.ImportFromGoenv(); err != nil {
+		t.Fatal(err)
+	}
+
+	installed, err := gb.InstalledVersions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(installed)
+	if want := []string{"1.20.5", "1.21.0"}; !reflect.DeepEqual(installed, want) {
+		t.Errorf("installed %v, want %v", installed, want)
+	}
+	if target, _ := os.Readlink(filepath.Join(gb.getVersionDir("1.21.0"), "go")); target != "" {
+		t.Errorf("expected the version gobrew had to be kept, it links to %s", target)
+	}
+	if err := gb.Verify("1.20.5"); err != nil {
+		t.Errorf("expected the imported version to verify: %s", err)
+	}
+
+	useFixture(t, gb, "1.20.5")
+	if cv := gb.CurrentVersion(); cv != "1.20.5" {
+		t.Errorf("expected the imported version usable, current is %q", cv)
+	}
+
+	gb.cleanVersionDir("1.20.5")
+	if _, err := os.Stat(filepath.Join(versions, "1.20.5", "bin", "go")); err != nil {
+		t.Errorf("uninstalling from gobrew should leave goenv alone: %s", err)
+	}
+}
+
+func TestImportFromGoenvMissing(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	os.Setenv("GOENV_ROOT", filepath.Join(tempDir(t), "missing"))
+	defer os.Unsetenv("GOENV_ROOT")
+
+	if err := gb.ImportFromGoenv(); err == nil {
+		t.Error("expected an error without a goenv install")
+	}
+}
+
+func TestImportFromGvm(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	gvmRoot := tempDir(t)
+	os.Setenv("GVM_ROOT", gvmRoot)
+	defer os.Unsetenv("GVM_ROOT")
+	writeGoroot(t, filepath.Join(gvmRoot, "gos", "go1.19.2"), "go version go1.19.2 linux/amd64")
+
+	if err := gb.ImportFromGvm(); err != nil {
+		t.Fatal(err)
+	}
+	if !gb.existsVersion("1.19.2") {
+		t.Error("expected go1.19.2 imported as 1.19.2")
+	}
+}
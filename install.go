@@ -0,0 +1,113 @@
+package gobrew
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/kevincobain2000/gobrew/utils"
+)
+
+// InstallResult tells what Install did, so provisioning scripts can tell
+// whether anything changed
+type InstallResult struct {
+	Version string
+	// AlreadyInstalled when nothing was done
+	AlreadyInstalled bool
+	// Downloaded is false when a cached archive was installed
+	Downloaded      bool
+	BytesDownloaded int64
+}
+
+// customVersion normalizes the version an archive is installed as like Install
+// does. Other names are kept as they are but must stay inside versionsDir
+func customVersion(version string) (string, error) {
+	if version == "" {
+		return "", fmt.Errorf("no version provided")
+	}
+	if isCommitVersion(version) {
+		return CommitVersion(version)
+	}
+	if normalized, err := normalizeVersion(version); err == nil {
+		return normalized, nil
+	}
+	// dot names are hidden as extractions in progress
+	if strings.ContainsAny(version, `/\`) || strings.Contains(version, "..") || strings.HasPrefix(version, ".") {
+		return "", fmt.Errorf("invalid version %q, it must not contain path separators or ..", version)
+	}
+	return version, nil
+}
+
+// InstallFromURL downloads the go archive at url and installs it as version.
+// When checksum is not empty the archive must hash to that sha256.
+func (gb *GoBrew) InstallFromURL(version string, url string, checksum string) error {
+	version, err := customVersion(version)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(gb.downloadsDir, os.ModePerm); err != nil {
+		return err
+	}
+
+	archive := filepath.Join(gb.downloadsDir, path.Base(url))
+	utils.ColorInfo.Fprintf(gb.stdout, "[Info] Downloading from: %s \n", url)
+	if err := gb.download(url, archive); err != nil {
+		os.Remove(archive)
+		return err
+	}
+	defer os.Remove(archive)
+	gb.countDownload(archive)
+
+	return gb.InstallFromFile(version, archive, checksum)
+}
+
+// InstallFromFile installs the go archive at archive as version.
+// When checksum is not empty the archive must hash to that sha256.
+func (gb *GoBrew) InstallFromFile(version string, archive string, checksum string) error {
+	version, err := customVersion(version)
+	if err != nil {
+		return err
+	}
+	unlock, err := gb.lock(version)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if gb.existsVersion(version) {
+		return fmt.Errorf("version %s is already installed", version)
+	}
+
+	if checksum != "" {
+		if err := verifyChecksum(archive, checksum); err != nil {
+			return err
+		}
+	}
+
+	if err := gb.mkdirs(version); err != nil {
+		return err
+	}
+	if err := gb.extractVersion(archive, version); err != nil {
+		gb.cleanVersionDir(version)
+		return err
+	}
+	if err := gb.checkBinSubpath(version); err != nil {
+		gb.cleanVersionDir(version)
+		return err
+	}
+	utils.ColorSuccess.Fprintf(gb.stdout, "[Success] Installed version: %s from %s\n", version, archive)
+	gb.runPostInstallHook(version)
+	return nil
+}
+
+// EnsureInstalled installs version unless it already is, returning whether
+// it installed it. It runs Install, returning errors rather than exiting. With
+// EnableVerifyExisting an installed version that fails Verify is reinstalled
+func (gb *GoBrew) EnsureInstalled(version string) (bool, error) {
+	result, err := gb.install(gb.versionName(version))
+	if err != nil {
+		return false, installCause(err)
+	}
+	return !result.AlreadyInstalled, nil
+}
@@ -0,0 +1,150 @@
+package gobrew
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInstallFromFileChecksum(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	archive := filepath.Join(tempDir(t), "go-custom.tar.gz")
+	writeTarGz(t, archive, map[string]string{"go/bin/go": "go"})
+	sum, err := fileSHA256(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = gb.InstallFromFile("1.16", archive, "0000")
+	if !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("expected ErrChecksumMismatch, got %v", err)
+	}
+	if gb.existsVersion("1.16") {
+		t.Fatal("a mismatching archive must not be installed")
+	}
+
+	if err := gb.InstallFromFile("1.16", archive, sum); err != nil {
+		t.Fatal(err)
+	}
+	if !gb.existsVersion("1.16") {
+		t.Error("expected 1.16 installed")
+	}
+}
+
+func TestInstallFromURLChecksum(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	dir := tempDir(t)
+	archive := filepath.Join(dir, "go-custom.tar.gz")
+	writeTarGz(t, archive, map[string]string{"go/bin/go": "go"})
+	sum, _ := fileSHA256(archive)
+	server := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	defer server.Close()
+
+	err := gb.InstallFromURL("1.16", server.URL+"/go-custom.tar.gz", "ffff")
+	if !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("expected ErrChecksumMismatch, got %v", err)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(gb.downloadsDir, "*")); len(matches) != 0 {
+		t.Errorf("expected the download cleaned up, found %v", matches)
+	}
+
+	if err := gb.InstallFromURL("1.16", server.URL+"/go-custom.tar.gz", sum); err != nil {
+		t.Fatal(err)
+	}
+	if !gb.existsVersion("1.16") {
+		t.Error("expected 1.16 installed")
+	}
+}
+
+func TestInstallFromFileMkdirsError(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	archive := filepath.Join(tempDir(t), "go-custom.tar.gz")
+	writeTarGz(t, archive, map[string]string{"go/bin/go": "go"})
+	// a file where the downloads dir should be
+	if err := ioutil.WriteFile(gb.downloadsDir, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := gb.mkdirs("1.16"); err == nil || !strings.Contains(err.Error(), "cannot create install directory") {
+		t.Errorf("expected mkdirs to fail, got %v", err)
+	}
+	err := gb.InstallFromFile("1.16", archive, "")
+	if err == nil || !strings.Contains(err.Error(), gb.downloadsDir) {
+		t.Errorf("expected the install to abort on the downloads dir, got %v", err)
+	}
+}
+
+func TestInstallResult(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	gb.registryPath = serveArchives(t, gb, "1.16").URL + "/"
+
+	fresh := gb.Install("go1.16")
+	if fresh.Version != "1.16" || fresh.AlreadyInstalled || !fresh.Downloaded || fresh.BytesDownloaded <= 0 {
+		t.Errorf("unexpected result of a fresh install %+v", fresh)
+	}
+
+	again := gb.Install("1.16")
+	if want := (InstallResult{Version: "1.16", AlreadyInstalled: true}); again != want {
+		t.Errorf("got %+v, want %+v", again, want)
+	}
+}
+
+func TestEnsureInstalled(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	gb.registryPath = serveArchives(t, gb, "1.16").URL + "/"
+	installFakeVersion(t, gb, "1.17", "go version go1.17 linux/amd64")
+
+	installed, err := gb.EnsureInstalled("go1.17")
+	if err != nil || installed {
+		t.Errorf("expected 1.17 present and left alone, got %v, %v", installed, err)
+	}
+
+	installed, err = gb.EnsureInstalled("1.16")
+	if err != nil || !installed {
+		t.Fatalf("expected 1.16 missing and installed, got %v, %v", installed, err)
+	}
+	if !gb.existsVersion("1.16") {
+		t.Fatal("expected 1.16 to be installed")
+	}
+	installed, err = gb.EnsureInstalled("1.16")
+	if err != nil || installed {
+		t.Errorf("expected a second call to install nothing, got %v, %v", installed, err)
+	}
+
+	if _, err := gb.EnsureInstalled("1.18"); err == nil {
+		t.Error("expected an error for a version the registry doesn't have")
+	}
+}
+
+func TestInstallFromFileVersionName(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	archive := filepath.Join(tempDir(t), "go-custom.tar.gz")
+	writeTarGz(t, archive, map[string]string{"go/bin/go": "go"})
+
+	if err := gb.InstallFromFile("go1.16", archive, ""); err != nil {
+		t.Fatal(err)
+	}
+	if !gb.existsVersion("1.16") {
+		t.Error("expected go1.16 installed as 1.16")
+	}
+	if err := gb.InstallFromFile("1.16", archive, ""); err == nil {
+		t.Error("expected 1.16 to be already installed")
+	}
+
+	for _, version := range []string{"../x", "a/b", `a\b`, "..", ".hidden"} {
+		if err := gb.InstallFromFile(version, archive, ""); err == nil {
+			t.Errorf("InstallFromFile(%q): expected an error", version)
+		}
+		if err := gb.InstallFromURL(version, "http://127.0.0.1:0/go-custom.tar.gz", ""); err == nil || !strings.Contains(err.Error(), "invalid version") {
+			t.Errorf("InstallFromURL(%q): expected the version rejected, got %v", version, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(gb.versionsDir), "x")); !os.IsNotExist(err) {
+		t.Error("expected nothing written outside the versions dir")
+	}
+}
@@ -0,0 +1,60 @@
+package gobrew
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaultBinSubpath is where the go binaries sit in a version dir, below the
+// toolchain root go
+const defaultBinSubpath = "go/bin"
+
+// parseBinSubpath validates GOBREW_BIN_SUBPATH, a slash separated path
+// relative to the version dir with at least the toolchain root and bin dir
+func parseBinSubpath(subpath string) (string, error) {
+	cleaned := filepath.ToSlash(filepath.Clean(filepath.FromSlash(subpath)))
+	if filepath.IsAbs(subpath) || cleaned == ".." || strings.HasPrefix(cleaned, "../") || !strings.Contains(cleaned, "/") {
+		return "", fmt.Errorf("GOBREW_BIN_SUBPATH %s must be relative to the version dir, like %s", subpath, defaultBinSubpath)
+	}
+	return cleaned, nil
+}
+
+// rootEntries are the names gobrew keeps at the top of its root: the dirs it
+// was configured with that sit there and the files and dirs it creates there
+func (gb *GoBrew) rootEntries() []string {
+	entries := []string{envDir, hooksDir, locksDir, systemSelectedFile, previousFile, aliasesFile, defaultFile,
+		releasesCacheFile, checksumsFile, metricsFile, protectedFile, toolsFile}
+	for _, dir := range []string{gb.versionsDir, gb.currentDir, filepath.Join(gb.installDir, "downloads"), gb.downloadsDir, gb.tmpDir} {
+		if dir != "" && filepath.Dir(dir) == gb.installDir {
+			entries = append(entries, filepath.Base(dir))
+		}
+	}
+	return entries
+}
+
+// versionBinDir of version, holding its go binary
+func (gb *GoBrew) versionBinDir(version string) string {
+	return filepath.Join(gb.installedVersionDir(version), filepath.FromSlash(gb.binSubpath))
+}
+
+// versionGoroot of version, the directory its bin dir sits in
+func (gb *GoBrew) versionGoroot(version string) string {
+	return filepath.Dir(gb.versionBinDir(version))
+}
+
+// rootSubpath is the toolchain root relative to a version dir, go by default
+func (gb *GoBrew) rootSubpath() string {
+	return filepath.Dir(filepath.FromSlash(gb.binSubpath))
+}
+
+// checkBinSubpath makes sure an installed version has its go binary where
+// GOBREW_BIN_SUBPATH says
+func (gb *GoBrew) checkBinSubpath(version string) error {
+	goBin := filepath.Join(gb.versionBinDir(version), exeName("go"))
+	if _, err := os.Stat(goBin); err != nil {
+		return fmt.Errorf("version %s has no go binary in %s, check GOBREW_BIN_SUBPATH: %w", version, gb.binSubpath, err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package gobrew
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseBinSubpath(t *testing.T) {
+	for subpath, want := range map[string]string{
+		"go/bin":          "go/bin",
+		"go/libexec/bin/": "go/libexec/bin",
+		"toolchain/tools": "toolchain/tools",
+		"bin":             "",
+		"/opt/go/bin":     "",
+		"../go/bin":       "",
+	} {
+		got, err := parseBinSubpath(subpath)
+		if (err != nil) != (want == "") || got != want {
+			t.Errorf("parseBinSubpath(%q) = (%q, %v), want %q", subpath, got, err, want)
+		}
+	}
+}
+
+func TestCustomBinSubpath(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	gb.binSubpath = "go/libexec/bin"
+	binDir := filepath.Join(gb.getVersionDir("1.16"), "go", "libexec", "bin")
+	os.MkdirAll(binDir, os.ModePerm)
+	ioutil.WriteFile(filepath.Join(binDir, "go"), []byte("#!/bin/sh\necho 'go version go1.16 linux/amd64'\n"), 0755)
+	ioutil.WriteFile(filepath.Join(binDir, "gofmt"), []byte("#!/bin/sh\n"), 0755)
+	os.MkdirAll(gb.currentDir, os.ModePerm)
+
+	if !gb.existsVersion("1.16") {
+		t.Fatal("expected 1.16 found in the custom layout")
+	}
+	gb.Use("1.16")
+	if cv := gb.CurrentVersion(); cv != "1.16" {
+		t.Errorf("expected current version 1.16, got %q", cv)
+	}
+	if target, _ := filepath.EvalSymlinks(gb.currentBinDir); target != binDir {
+		t.Errorf("expected %s linked to %s, got %s", gb.currentBinDir, binDir, target)
+	}
+	if target, _ := filepath.EvalSymlinks(gb.currentGoDir); target != filepath.Dir(binDir) {
+		t.Errorf("expected %s linked to %s, got %s", gb.currentGoDir, filepath.Dir(binDir), target)
+	}
+	if err := gb.Verify("1.16"); err != nil {
+		t.Errorf("expected the custom layout to verify: %s", err)
+	}
+}
+
+func TestInstallChecksBinSubpath(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	gb.registryPath = serveArchives(t, gb, "1.16").URL + "/"
+	gb.binSubpath = "go/libexec/bin"
+	var stderr bytes.Buffer
+	gb.stderr = &stderr
+	var exitCode int
+	osExit = func(code int) { exitCode = code }
+	defer func() { osExit = os.Exit }()
+
+	gb.Install("1.16")
+
+	if exitCode != 1 {
+		t.Errorf("expected exit code 1, got %d", exitCode)
+	}
+	if _, err := os.Stat(gb.getVersionDir("1.16")); !os.IsNotExist(err) {
+		t.Error("expected the version dir cleaned up")
+	}
+	if !bytes.Contains(stderr.Bytes(), []byte("GOBREW_BIN_SUBPATH")) {
+		t.Errorf("expected the error to point at GOBREW_BIN_SUBPATH, got %q", stderr.String())
+	}
+}
@@ -0,0 +1,46 @@
+//go:build !windows
+// +build !windows
+
+package gobrew
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// resolveLink returns the target of the link at path, failing when it dangles
+func resolveLink(path string) (string, error) {
+	fp, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", err
+	}
+	// read the link itself, the go dir of external versions is a symlink too
+	if link, err := os.Readlink(path); err == nil {
+		fp = link
+	}
+	return fp, nil
+}
+
+// replaceLink points link at target in a single rename, so readers of link
+// see either the old or the new target and never a missing link
+func replaceLink(target, link string) error {
+	tmp := link + ".tmp-" + strconv.Itoa(os.Getpid())
+	os.Remove(tmp)
+	if err := os.Symlink(target, tmp); err != nil {
+		return err
+	}
+	err := os.Rename(tmp, link)
+	if err != nil {
+		// rename won't replace a real directory, e.g. one left by hand
+		if info, lerr := os.Lstat(link); lerr == nil && info.IsDir() {
+			if err = os.RemoveAll(link); err == nil {
+				err = os.Rename(tmp, link)
+			}
+		}
+	}
+	if err != nil {
+		os.Remove(tmp)
+	}
+	return err
+}
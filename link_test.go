@@ -0,0 +1,30 @@
+package gobrew
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestCurrentVersionJunction(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		t.Skip("directory junctions only exist on windows")
+	}
+	gb := newTestGoBrew(tempDir(t))
+	binDir := filepath.Join(gb.getVersionDir("1.16"), "go", "bin")
+	os.MkdirAll(binDir, os.ModePerm)
+	os.MkdirAll(gb.currentDir, os.ModePerm)
+	if output, err := exec.Command("cmd", "/c", "mklink", "/J", gb.currentBinDir, binDir).CombinedOutput(); err != nil {
+		t.Fatalf("mklink /J: %s: %s", err, output)
+	}
+
+	if cv := gb.CurrentVersion(); cv != "1.16" {
+		t.Errorf("expected 1.16 detected through the junction, got %q", cv)
+	}
+	os.RemoveAll(gb.getVersionDir("1.16"))
+	if cv := gb.CurrentVersion(); cv != "" {
+		t.Errorf("expected no current version through a dangling junction, got %q", cv)
+	}
+}
@@ -0,0 +1,32 @@
+package gobrew
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// resolveLink returns the target of the link at path, failing when it dangles.
+// Directory junctions stand in for symlinks where those need privileges, and
+// filepath.EvalSymlinks fails on some of them, so the reparse point is read
+// with os.Readlink, which understands both
+func resolveLink(path string) (string, error) {
+	if _, err := os.Stat(path); err != nil {
+		return "", err
+	}
+	link, err := os.Readlink(path)
+	if err != nil {
+		return filepath.EvalSymlinks(path)
+	}
+	// junction targets may keep the NT namespace prefix
+	return strings.TrimPrefix(link, `\??\`), nil
+}
+
+// replaceLink points link at target. Renaming over a directory link fails on
+// windows, so unlike elsewhere the link is briefly missing
+func replaceLink(target, link string) error {
+	if err := os.RemoveAll(link); err != nil {
+		return err
+	}
+	return os.Symlink(target, link)
+}
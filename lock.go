@@ -0,0 +1,37 @@
+package gobrew
+
+import (
+	"os"
+	"path/filepath"
+)
+
+const (
+	locksDir = "locks"
+	// downloadsLock guards the downloads dir shared by every install
+	downloadsLock = "downloads"
+	// currentLock guards the current links, so concurrent switches never
+	// leave bin and go pointing at different versions
+	currentLock = "current"
+)
+
+// lock blocks until this process holds the lock called name, so distinct
+// versions install concurrently while the same version never installs twice
+// at once. The lock is released by unlock, or when the process exits
+func (gb *GoBrew) lock(name string) (unlock func(), err error) {
+	dir := filepath.Join(gb.installDir, locksDir)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+	gb.debug.Printf("waiting for lock %s", name)
+	return lockFile(filepath.Join(dir, name+".lock"))
+}
+
+// withCurrentLock runs fn holding the lock of the current links
+func (gb *GoBrew) withCurrentLock(fn func() error) error {
+	unlock, err := gb.lock(currentLock)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fn()
+}
@@ -0,0 +1,139 @@
+//go:build !windows
+// +build !windows
+
+package gobrew
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLockSameVersion(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	unlock, err := gb.lock("1.16")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	locked := make(chan func())
+	go func() {
+		second, err := gb.lock("1.16")
+		if err != nil {
+			t.Error(err)
+		}
+		locked <- second
+	}()
+	other, err := gb.lock("1.17")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other()
+
+	select {
+	case <-locked:
+		t.Fatal("expected the second lock of 1.16 to wait")
+	case <-time.After(100 * time.Millisecond):
+	}
+	unlock()
+	select {
+	case second := <-locked:
+		second()
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the lock of 1.16 acquired once released")
+	}
+}
+
+func TestConcurrentInstalls(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	archives := serveArchives(t, gb, "1.16", "1.17")
+
+	// hold each download until both installs are downloading
+	var arrived sync.WaitGroup
+	arrived.Add(2)
+	both := make(chan struct{})
+	go func() { arrived.Wait(); close(both) }()
+	var serialized bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		arrived.Done()
+		select {
+		case <-both:
+		case <-time.After(5 * time.Second):
+			serialized = true
+		}
+		http.Redirect(w, r, archives.URL+r.URL.Path, http.StatusFound)
+	}))
+	defer server.Close()
+	gb.registryPath = server.URL + "/"
+
+	var wg sync.WaitGroup
+	for _, version := range []string{"1.16", "1.17"} {
+		wg.Add(1)
+		go func(gb GoBrew, version string) {
+			defer wg.Done()
+			gb.Install(version)
+		}(gb, version)
+	}
+	wg.Wait()
+
+	if serialized {
+		t.Error("expected installs of distinct versions to download in parallel")
+	}
+	for _, version := range []string{"1.16", "1.17"} {
+		if !gb.existsVersion(version) {
+			t.Errorf("expected %s installed", version)
+		}
+	}
+}
+
+func TestConcurrentUse(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	versions := []string{"1.16", "1.17", "1.18"}
+	for _, version := range versions {
+		installFakeVersion(t, gb, version, "go version go"+version+" linux/amd64")
+	}
+	os.MkdirAll(gb.currentDir, os.ModePerm)
+	gb.Use("1.16")
+
+	done := make(chan struct{})
+	var seen []string
+	var reader sync.WaitGroup
+	reader.Add(1)
+	go func() {
+		defer reader.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				seen = append(seen, gb.CurrentVersion())
+			}
+		}
+	}()
+
+	var switches sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		switches.Add(1)
+		go func(i int) {
+			defer switches.Done()
+			for j := 0; j < 20; j++ {
+				gb.Use(versions[(i+j)%len(versions)])
+			}
+		}(i)
+	}
+	switches.Wait()
+	close(done)
+	reader.Wait()
+
+	for _, cv := range seen {
+		if cv != "1.16" && cv != "1.17" && cv != "1.18" {
+			t.Fatalf("expected current to always resolve to an installed version, got %q", cv)
+		}
+	}
+	if err := gb.CheckSymlinkConsistency(); err != nil {
+		t.Errorf("expected the current links to agree after concurrent switches, got %s", err)
+	}
+}
@@ -0,0 +1,82 @@
+package gobrew
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// manifestFile lists every file extracted into a version dir, sitting next to
+// its go directory
+const manifestFile = "manifest.json"
+
+// manifestEntry is a regular file of the toolchain, path is slash separated and
+// relative to the version dir
+type manifestEntry struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+// writeManifest records the regular files under versionDir/go
+func writeManifest(versionDir string) error {
+	entries := make([]manifestEntry, 0)
+	err := filepath.Walk(filepath.Join(versionDir, "go"), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(versionDir, path)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, manifestEntry{Path: filepath.ToSlash(rel), Size: info.Size()})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+
+	content, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(versionDir, manifestFile), content, 0644)
+}
+
+// VerifyManifest checks the files of an installed version against the manifest
+// written when it was extracted. Only sizes are compared, which is enough to
+// catch an interrupted or partially deleted tree without running anything.
+// Versions installed before manifests existed have nothing to check, a version
+// only found in the system root is checked there
+func (gb *GoBrew) VerifyManifest(version string) error {
+	versionDir := gb.installedVersionDir(version)
+	content, err := ioutil.ReadFile(filepath.Join(versionDir, manifestFile))
+	if os.IsNotExist(err) {
+		gb.debug.Printf("no manifest for version %s", version)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var entries []manifestEntry
+	if err := json.Unmarshal(content, &entries); err != nil {
+		return fmt.Errorf("version %s has an invalid manifest: %s", version, err)
+	}
+
+	for _, entry := range entries {
+		info, err := os.Stat(filepath.Join(versionDir, filepath.FromSlash(entry.Path)))
+		if err != nil {
+			return fmt.Errorf("version %s is corrupt: %s", version, err)
+		}
+		if info.Size() != entry.Size {
+			return fmt.Errorf("version %s is corrupt: %s is %d bytes, expected %d", version, entry.Path, info.Size(), entry.Size)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package gobrew
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVerifyManifest(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	gb.registryPath = serveArchives(t, gb, "1.16", "1.17").URL + "/"
+	gb.Install("1.16")
+	gb.Install("1.17")
+
+	for _, version := range []string{"1.16", "1.17"} {
+		if err := gb.VerifyManifest(version); err != nil {
+			t.Fatalf("%s: expected a fresh install to match its manifest: %s", version, err)
+		}
+	}
+
+	os.Remove(filepath.Join(gb.getVersionDir("1.16"), "go", "bin", "gofmt"))
+	if err := gb.VerifyManifest("1.16"); err == nil {
+		t.Error("expected the deleted gofmt to be detected")
+	}
+	if err := gb.Verify("1.16"); err == nil {
+		t.Error("expected Verify to fail on the manifest")
+	}
+
+	ioutil.WriteFile(filepath.Join(gb.getVersionDir("1.17"), "go", "bin", "go"), []byte("#!"), 0755)
+	if err := gb.VerifyManifest("1.17"); err == nil {
+		t.Error("expected the truncated go to be detected")
+	}
+}
+
+func TestVerifyManifestMissing(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	installFakeVersion(t, gb, "1.16", "go version go1.16 linux/amd64")
+
+	if err := gb.VerifyManifest("1.16"); err != nil {
+		t.Errorf("a version without a manifest should pass: %s", err)
+	}
+}
+
+func TestVerifyManifestSystemRoot(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	system := newTestGoBrew(tempDir(t))
+	system.registryPath = serveArchives(t, system, "1.16").URL + "/"
+	system.Install("1.16")
+	gb.systemVersionsDir = system.versionsDir
+
+	if err := gb.VerifyManifest("1.16"); err != nil {
+		t.Fatalf("expected the system version to match its manifest: %s", err)
+	}
+	os.Remove(filepath.Join(system.getVersionDir("1.16"), "go", "bin", "gofmt"))
+	if err := gb.VerifyManifest("1.16"); err == nil {
+		t.Error("expected the deleted gofmt in the system root to be detected")
+	}
+}
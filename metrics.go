@@ -0,0 +1,84 @@
+package gobrew
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const metricsFile = "metrics.jsonl"
+
+// MetricEntry is one command recorded when GOBREW_METRICS=1. Metrics are
+// only ever appended to a local file, nothing is sent anywhere
+type MetricEntry struct {
+	Time     time.Time     `json:"time"`
+	Command  string        `json:"command"`
+	Version  string        `json:"version,omitempty"`
+	Duration time.Duration `json:"duration"`
+	// Bytes downloaded while running the command
+	Bytes int64 `json:"bytes"`
+}
+
+// RecordMetric appends command to the metrics file, when enabled
+func (gb *GoBrew) RecordMetric(command string, version string, duration time.Duration) error {
+	if !gb.metrics {
+		return nil
+	}
+	if err := os.MkdirAll(gb.installDir, os.ModePerm); err != nil {
+		return err
+	}
+	line, err := json.Marshal(MetricEntry{
+		Time:     time.Now(),
+		Command:  command,
+		Version:  version,
+		Duration: duration,
+		Bytes:    gb.downloaded,
+	})
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath.Join(gb.installDir, metricsFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Metrics reads back the recorded metrics, oldest first
+func (gb *GoBrew) Metrics() ([]MetricEntry, error) {
+	f, err := os.Open(filepath.Join(gb.installDir, metricsFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []MetricEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry MetricEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// countDownload adds the size of the downloaded archive to the bytes recorded
+func (gb *GoBrew) countDownload(archive string) {
+	if fi, err := os.Stat(archive); err == nil {
+		gb.downloaded += fi.Size()
+	}
+}
@@ -0,0 +1,63 @@
+package gobrew
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMetrics(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	gb.metrics = true
+	gb.registryPath = serveArchives(t, gb, "1.16").URL + "/"
+
+	if err := gb.mkdirs("1.16"); err != nil {
+		t.Fatal(err)
+	}
+	gb.downloadAndExtract("1.16")
+	if err := gb.RecordMetric("install", "1.16", 2*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	gb.downloaded = 0
+	if err := gb.RecordMetric("ls", "", time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := gb.Metrics()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", entries)
+	}
+	install := entries[0]
+	if install.Command != "install" || install.Version != "1.16" || install.Duration != 2*time.Second || install.Bytes == 0 || install.Time.IsZero() {
+		t.Errorf("unexpected entry %+v", install)
+	}
+	if entries[1].Command != "ls" || entries[1].Bytes != 0 {
+		t.Errorf("unexpected entry %+v", entries[1])
+	}
+}
+
+func TestMetricsDisabled(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	os.MkdirAll(gb.installDir, os.ModePerm)
+
+	if err := gb.RecordMetric("install", "1.16", time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(gb.installDir, metricsFile)); !os.IsNotExist(err) {
+		t.Errorf("expected no metrics file when disabled, got %v", err)
+	}
+	entries, err := gb.Metrics()
+	if err != nil || len(entries) != 0 {
+		t.Errorf("expected no entries, got %v %v", entries, err)
+	}
+
+	ioutil.WriteFile(filepath.Join(gb.installDir, metricsFile), []byte("not json\n"), 0644)
+	if _, err := gb.Metrics(); err == nil {
+		t.Error("expected an error for a corrupt metrics file")
+	}
+}
@@ -0,0 +1,63 @@
+package gobrew
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/kevincobain2000/gobrew/utils"
+)
+
+// parseMirrors splits GOBREW_MIRRORS, a comma separated list of base URLs
+func parseMirrors(list string) []string {
+	var mirrors []string
+	for _, mirror := range strings.Split(list, ",") {
+		if mirror = strings.TrimSpace(mirror); mirror != "" {
+			mirrors = append(mirrors, mirror)
+		}
+	}
+	return mirrors
+}
+
+// fetchArchive downloads the archive of version to archive from the registry.
+// When that fails or it doesn't hash to expected it is downloaded again from
+// each mirror in turn, so an unreachable or corrupt mirror doesn't fail the
+// install. An empty expected checksum is not checked
+func (gb *GoBrew) fetchArchive(version string, archive string, expected string) error {
+	if expected == "" {
+		return gb.fetchVerified(version, archive, nil)
+	}
+	return gb.fetchVerified(version, archive, func() error {
+		return verifyChecksum(archive, expected)
+	})
+}
+
+// fetchVerified is fetchArchive with the check left to verify, e.g. to hash
+// the archive while extracting it. A failed download or ErrChecksumMismatch
+// moves on to the next mirror, a nil verify accepts any archive
+func (gb *GoBrew) fetchVerified(version string, archive string, verify func() error) error {
+	var err error
+	for i, registry := range append([]string{gb.registryPath}, gb.mirrors...) {
+		if i > 0 {
+			utils.ColorInfo.Fprintf(gb.stdout, "[Info] Retrying from mirror: %s \n", registry)
+		}
+		if err = gb.download(registry+gb.archiveName(version), archive); err != nil {
+			os.Remove(archive)
+			if errors.Is(err, ErrOfflineMode) {
+				return err
+			}
+			utils.ColorInfo.Fprintf(gb.stdout, "[Info] %s, downloading from: %s \n", err, registry)
+			continue
+		}
+		gb.countDownload(archive)
+		if verify == nil {
+			return nil
+		}
+		if err = verify(); !errors.Is(err, ErrChecksumMismatch) {
+			return err
+		}
+		os.Remove(archive)
+		utils.ColorInfo.Fprintf(gb.stdout, "[Info] %s, downloaded from: %s \n", err, registry)
+	}
+	return err
+}
@@ -0,0 +1,70 @@
+package gobrew
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestParseMirrors(t *testing.T) {
+	got := parseMirrors(" https://a.example.com/go/, ,https://b.example.com/ ")
+	if want := []string{"https://a.example.com/go/", "https://b.example.com/"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestInstallRetriesMirrorOnChecksumMismatch(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	dir := tempDir(t)
+	name := gb.archiveName("1.16")
+	writeTarGz(t, filepath.Join(dir, name), map[string]string{
+		"go/bin/go":    "#!/bin/sh\necho 'go version go1.16 " + strings.Replace(gb.getArch(), "-", "/", 1) + "'\n",
+		"go/bin/gofmt": "#!/bin/sh\n",
+	})
+	sum, err := fileSHA256(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := json.Marshal([]Release{{Version: "go1.16", Stable: true, Files: []Build{{
+		Filename: name, OS: runtime.GOOS, Arch: archiveArch(runtime.GOOS, runtime.GOARCH), Kind: "archive", SHA256: sum,
+	}}}})
+	serveReleases(t, &gb, string(body))
+
+	corrupt := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("corrupt"))
+	}))
+	defer corrupt.Close()
+	mirror := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	defer mirror.Close()
+	gb.registryPath = corrupt.URL + "/"
+
+	archive := filepath.Join(tempDir(t), name)
+	if err := gb.fetchArchive("1.16", archive, sum); !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("expected ErrChecksumMismatch without mirrors, got %v", err)
+	}
+
+	gb.mirrors = []string{mirror.URL + "/"}
+	gb.Install("1.16")
+	if !gb.existsVersion("1.16") {
+		t.Error("expected 1.16 installed from the mirror")
+	}
+}
+
+func TestInstallRetriesMirrorOnHTTPError(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	missing := httptest.NewServer(http.NotFoundHandler())
+	defer missing.Close()
+	gb.registryPath = missing.URL + "/"
+	gb.mirrors = []string{serveArchives(t, gb, "1.16").URL + "/"}
+
+	gb.Install("1.16")
+	if !gb.existsVersion("1.16") {
+		t.Error("expected 1.16 installed from the mirror after a 404 from the registry")
+	}
+}
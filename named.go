@@ -0,0 +1,43 @@
+package gobrew
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kevincobain2000/gobrew/utils"
+)
+
+// UseNamed links version as the named current <root>/<name>/bin and
+// <root>/<name>/go, next to the usual current. Putting a different named bin
+// dir on PATH per project runs toolchains side by side. Names gobrew keeps in
+// its root are refused
+func (gb *GoBrew) UseNamed(name string, version string) error {
+	if name == "" || strings.HasPrefix(name, ".") || strings.ContainsAny(name, `/\`) || utils.Find(gb.rootEntries(), name) {
+		return fmt.Errorf("invalid name %q", name)
+	}
+	version = gb.versionName(version)
+	if !gb.existsVersion(version) {
+		return fmt.Errorf("version %s is not installed", version)
+	}
+
+	dir := filepath.Join(gb.installDir, name)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	goDir := gb.versionGoroot(version)
+	for link, target := range map[string]string{
+		filepath.Join(dir, "bin"): gb.versionBinDir(version),
+		filepath.Join(dir, "go"):  goDir,
+	} {
+		if err := os.RemoveAll(link); err != nil {
+			return err
+		}
+		if err := os.Symlink(target, link); err != nil {
+			return err
+		}
+	}
+	utils.ColorSuccess.Fprintf(gb.stdout, "[Success] Changed %s go version to: %s, add %s to your PATH\n", name, version, filepath.Join(dir, "bin"))
+	return nil
+}
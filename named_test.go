@@ -0,0 +1,54 @@
+package gobrew
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestUseNamed(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	installSizedVersion(t, gb, "1.16", 10)
+	installSizedVersion(t, gb, "1.17", 10)
+	useFixture(t, gb, "1.17")
+
+	if err := gb.UseNamed("legacy", "1.16"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gb.UseNamed("next", "go1.17"); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]string{"legacy": "1.16", "next": "1.17", "": "1.17"} {
+		if got := gb.CurrentVersion(name); got != want {
+			t.Errorf("expected %q current to be %s, got %s", name, want, got)
+		}
+	}
+	got, err := filepath.EvalSymlinks(filepath.Join(gb.installDir, "legacy", "go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := filepath.EvalSymlinks(filepath.Join(gb.getVersionDir("1.16"), "go"))
+	if got != want {
+		t.Errorf("expected legacy/go to resolve to %s, got %s", want, got)
+	}
+
+	// switching a named current leaves the others alone
+	if err := gb.UseNamed("legacy", "1.17"); err != nil {
+		t.Fatal(err)
+	}
+	if gb.CurrentVersion("legacy") != "1.17" || gb.CurrentVersion() != "1.17" {
+		t.Errorf("expected legacy switched to 1.17")
+	}
+
+	for _, name := range []string{"", "versions", "downloads", "current", "hooks", "locks", "system", "default", ".hidden", "a/b"} {
+		if err := gb.UseNamed(name, "1.16"); err == nil {
+			t.Errorf("expected an error for name %q", name)
+		}
+	}
+	if gb.systemSelected() {
+		t.Error("a named current must not select the system go")
+	}
+	if err := gb.UseNamed("other", "1.99"); err == nil {
+		t.Error("expected an error for a version that is not installed")
+	}
+}
@@ -0,0 +1,25 @@
+package gobrew
+
+import "fmt"
+
+// releaseNotesURL is the prefix of the release notes of every minor line
+const releaseNotesURL = "https://go.dev/doc/go"
+
+// ReleaseNotesURL returns the release notes of the minor line of version, so
+// patches and prereleases point at the notes of the release they lead to.
+// It returns "" when version is not a go version
+func ReleaseNotesURL(version string) string {
+	normalized, err := normalizeVersion(version)
+	if err != nil {
+		return ""
+	}
+	v, ok := parseGoVersion(normalized)
+	if !ok {
+		return ""
+	}
+	// Go 1 has a single page, go1.0 does not exist
+	if v.major == 1 && v.minor == 0 {
+		return releaseNotesURL + "1"
+	}
+	return fmt.Sprintf("%s%d.%d", releaseNotesURL, v.major, v.minor)
+}
@@ -0,0 +1,22 @@
+package gobrew
+
+import "testing"
+
+func TestReleaseNotesURL(t *testing.T) {
+	tests := map[string]string{
+		"1.22":     "https://go.dev/doc/go1.22",
+		"1.22.3":   "https://go.dev/doc/go1.22",
+		"go1.21.0": "https://go.dev/doc/go1.21",
+		"1.23rc1":  "https://go.dev/doc/go1.23",
+		"v1.9.2":   "https://go.dev/doc/go1.9",
+		"1":        "https://go.dev/doc/go1",
+		"1.0.3":    "https://go.dev/doc/go1",
+		"latest":   "",
+		"":         "",
+	}
+	for version, want := range tests {
+		if got := ReleaseNotesURL(version); got != want {
+			t.Errorf("ReleaseNotesURL(%q): expected %q, got %q", version, want, got)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+package gobrew
+
+import (
+	"errors"
+)
+
+// ErrOfflineMode is returned by everything that needs the network when
+// GOBREW_NO_NETWORK=1
+var ErrOfflineMode = errors.New("offline mode, GOBREW_NO_NETWORK=1")
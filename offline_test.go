@@ -0,0 +1,61 @@
+package gobrew
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func TestOfflineRefusesNetwork(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	gb.offline = true
+	// git would hang in CI, it must not even run
+	fakeBin(t, "git", "exec sleep 60\n")
+	serveReleases(t, &gb, releasesFixture)
+
+	if _, err := gb.RemoteVersions(); !errors.Is(err, ErrOfflineMode) {
+		t.Errorf("RemoteVersions: expected ErrOfflineMode, got %v", err)
+	}
+	if _, err := gb.fetchReleases(); !errors.Is(err, ErrOfflineMode) {
+		t.Errorf("fetchReleases: expected ErrOfflineMode, got %v", err)
+	}
+	if _, err := gb.RemoteVersionsWithBuilds(); !errors.Is(err, ErrOfflineMode) {
+		t.Errorf("RemoteVersionsWithBuilds: expected ErrOfflineMode, got %v", err)
+	}
+	dest := filepath.Join(tempDir(t), "go.tar.gz")
+	if err := gb.download(gb.archiveURL("1.16"), dest); !errors.Is(err, ErrOfflineMode) {
+		t.Errorf("download: expected ErrOfflineMode, got %v", err)
+	}
+	if err := gb.InstallFromURL("1.16", gb.archiveURL("1.16"), ""); !errors.Is(err, ErrOfflineMode) {
+		t.Errorf("InstallFromURL: expected ErrOfflineMode, got %v", err)
+	}
+}
+
+func TestOfflineInstallsFromCache(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	archive := filepath.Join(gb.downloadsDir, gb.archiveName("1.16"))
+	writeTarGz(t, archive, map[string]string{"go/bin/go": "go"})
+	sum, _ := fileSHA256(archive)
+	// cache the releases while online, however long ago
+	serveReleases(t, &gb, fmt.Sprintf(`[{"version": "go1.16", "stable": true, "files": [{"filename": %q, "sha256": %q}]}]`, gb.archiveName("1.16"), sum))
+	if _, err := gb.fetchReleases(); err != nil {
+		t.Fatal(err)
+	}
+	gb.releasesCacheTTL = 0
+	gb.offline = true
+
+	if err := gb.mkdirs("1.16"); err != nil {
+		t.Fatal(err)
+	}
+	gb.downloadAndExtract("1.16")
+	if !gb.existsVersion("1.16") {
+		t.Error("expected 1.16 installed from the cached archive")
+	}
+
+	local := filepath.Join(tempDir(t), "go-custom.tar.gz")
+	writeTarGz(t, local, map[string]string{"go/bin/go": "go"})
+	if err := gb.InstallFromFile("1.17", local, ""); err != nil {
+		t.Errorf("expected installing from a local file to work offline: %s", err)
+	}
+}
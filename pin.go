@@ -0,0 +1,56 @@
+package gobrew
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/kevincobain2000/gobrew/utils"
+)
+
+// goVersionFile pins the go version of a project directory
+const goVersionFile = ".go-version"
+
+// Pin writes version, normalized like Install does, or the current version
+// when empty, into the .go-version file of the working directory
+func (gb *GoBrew) Pin(version string) error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	return gb.pinDir(dir, version)
+}
+
+func (gb *GoBrew) pinDir(dir string, version string) error {
+	if version == "" {
+		version = gb.CurrentVersion()
+		if version == "" {
+			return fmt.Errorf("no version provided and no current version in use")
+		}
+	} else if isCommitVersion(version) {
+		commit, err := CommitVersion(version)
+		if err != nil {
+			return err
+		}
+		version = commit
+	} else if !isExternalVersion(version) {
+		normalized, err := normalizeVersion(version)
+		if err != nil {
+			return err
+		}
+		version = normalized
+	}
+
+	if !gb.existsVersion(version) {
+		versions, err := gb.RemoteVersions()
+		if err != nil {
+			return fmt.Errorf("version %s is not installed and remote versions could not be fetched: %s", version, err)
+		}
+		if !utils.Find(versions, version) {
+			return fmt.Errorf("version %s is neither installed nor available remotely", version)
+		}
+	}
+
+	return ioutil.WriteFile(filepath.Join(dir, goVersionFile), []byte(version+"\n"), 0644)
+}
@@ -0,0 +1,65 @@
+package gobrew
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPin(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	installFakeVersion(t, gb, "1.16.3", "go version go1.16.3 linux/amd64")
+
+	project := tempDir(t)
+	if err := gb.pinDir(project, "1.16.3"); err != nil {
+		t.Fatal(err)
+	}
+	content, err := ioutil.ReadFile(filepath.Join(project, goVersionFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "1.16.3\n" {
+		t.Errorf("got %q, want %q", content, "1.16.3\n")
+	}
+}
+
+func TestPinCurrentVersion(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	installFakeVersion(t, gb, "1.17", "go version go1.17 linux/amd64")
+	os.MkdirAll(gb.currentDir, os.ModePerm)
+	os.Symlink(filepath.Join(gb.getVersionDir("1.17"), "go", "bin"), gb.currentBinDir)
+
+	project := tempDir(t)
+	if err := gb.pinDir(project, ""); err != nil {
+		t.Fatal(err)
+	}
+	content, _ := ioutil.ReadFile(filepath.Join(project, goVersionFile))
+	if string(content) != "1.17\n" {
+		t.Errorf("got %q, want %q", content, "1.17\n")
+	}
+}
+
+func TestPinRemoteVersion(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	serveReleases(t, &gb, releasesFixture)
+	fakeBin(t, "git", "exit 128\n")
+	project := tempDir(t)
+
+	for _, version := range []string{"go1.17.6", "1.17.6"} {
+		if err := gb.pinDir(project, version); err != nil {
+			t.Fatalf("%s: %s", version, err)
+		}
+		content, _ := ioutil.ReadFile(filepath.Join(project, goVersionFile))
+		if string(content) != "1.17.6\n" {
+			t.Errorf("%s: got %q, want %q", version, content, "1.17.6\n")
+		}
+	}
+
+	if err := gb.pinDir(project, "1.99.1"); err == nil {
+		t.Error("expected an error for a version neither installed nor released")
+	}
+	if err := gb.pinDir(project, "not-a-version"); err == nil {
+		t.Error("expected an error for an invalid version")
+	}
+}
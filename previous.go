@@ -0,0 +1,47 @@
+package gobrew
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kevincobain2000/gobrew/utils"
+)
+
+// previousFile records the version Use switched away from
+const previousFile = "previous"
+
+// PreviousVersion returns the version used before the current one, empty
+// when there was none
+func (gb *GoBrew) PreviousVersion() (string, error) {
+	content, err := ioutil.ReadFile(filepath.Join(gb.installDir, previousFile))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(utils.BytesToString(content)), nil
+}
+
+// UsePrevious switches back to the version used before the current one, like `cd -`
+func (gb *GoBrew) UsePrevious() error {
+	previous, err := gb.PreviousVersion()
+	if err != nil {
+		return err
+	}
+	if previous == "" {
+		return fmt.Errorf("no previous version, use a version first")
+	}
+	if previous != systemVersion && !gb.existsVersion(previous) {
+		return fmt.Errorf("previous version %s is no longer installed", previous)
+	}
+	gb.Use(previous)
+	return nil
+}
+
+func (gb *GoBrew) setPreviousVersion(version string) error {
+	return ioutil.WriteFile(filepath.Join(gb.installDir, previousFile), []byte(version+"\n"), 0644)
+}
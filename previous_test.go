@@ -0,0 +1,33 @@
+package gobrew
+
+import (
+	"os"
+	"testing"
+)
+
+func TestUsePrevious(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	installFakeVersion(t, gb, "1.16", "go version go1.16 linux/amd64")
+	installFakeVersion(t, gb, "1.17", "go version go1.17 linux/amd64")
+	os.MkdirAll(gb.currentDir, os.ModePerm)
+
+	gb.Use("1.16")
+	if err := gb.UsePrevious(); err == nil {
+		t.Error("expected an error without a previous version")
+	}
+
+	gb.Use("1.17")
+	if err := gb.UsePrevious(); err != nil {
+		t.Fatal(err)
+	}
+	if cv := gb.CurrentVersion(); cv != "1.16" {
+		t.Errorf("expected back to 1.16, got %s", cv)
+	}
+	// and forth again
+	if err := gb.UsePrevious(); err != nil {
+		t.Fatal(err)
+	}
+	if cv := gb.CurrentVersion(); cv != "1.17" {
+		t.Errorf("expected back to 1.17, got %s", cv)
+	}
+}
@@ -0,0 +1,111 @@
+package gobrew
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// ProgressStage of an install reported by InstallWithProgress
+type ProgressStage string
+
+// Stages of InstallWithProgress, in the order they are reported
+const (
+	StageDownloading ProgressStage = "downloading"
+	StageExtracting  ProgressStage = "extracting"
+	StageVerifying   ProgressStage = "verifying"
+	StageDone        ProgressStage = "done"
+)
+
+// progressInterval between two Downloading events
+const progressInterval = 100 * time.Millisecond
+
+// ProgressEvent reports how far an install got
+type ProgressEvent struct {
+	Stage   ProgressStage
+	Version string
+	// Bytes downloaded so far out of Total, which is 0 when the JSON API
+	// doesn't publish the size. Downloading only
+	Bytes int64
+	Total int64
+	// Files extracted so far. Extracting only
+	Files int
+	// Err why the install failed, on Done only
+	Err error
+}
+
+// InstallWithProgress installs version like Install, returning errors instead
+// of exiting and reporting each stage on events for a frontend to render.
+// Events are dropped rather than waiting for a slow consumer, so the last
+// ones are only guaranteed with a buffered channel; events is closed once the
+// install is done either way
+func (gb *GoBrew) InstallWithProgress(version string, events chan<- ProgressEvent) (err error) {
+	defer close(events)
+	name := version
+	if normalized, err := normalizeVersion(version); err == nil {
+		name = normalized
+	}
+	defer func() {
+		sendProgress(events, ProgressEvent{Stage: StageDone, Version: name, Err: err})
+	}()
+
+	gb.report = func(e ProgressEvent) {
+		e.Version = name
+		sendProgress(events, e)
+	}
+	defer func() { gb.report = nil }()
+	_, err = gb.install(version)
+	return installCause(err)
+}
+
+// reportProgress hands e to gb.report, when an install is being reported
+func (gb *GoBrew) reportProgress(e ProgressEvent) {
+	if gb.report != nil {
+		gb.report(e)
+	}
+}
+
+// reportDownload reports the size of archive as it grows whatever the
+// downloader, out of total, until stop is called
+func (gb *GoBrew) reportDownload(archive string, total int64) (stop func()) {
+	if gb.report == nil {
+		return func() {}
+	}
+	gb.reportProgress(ProgressEvent{Stage: StageDownloading, Total: total})
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if fi, err := os.Stat(archive); err == nil {
+					gb.reportProgress(ProgressEvent{Stage: StageDownloading, Bytes: fi.Size(), Total: total})
+				}
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-stopped
+			if fi, err := os.Stat(archive); err == nil {
+				gb.reportProgress(ProgressEvent{Stage: StageDownloading, Bytes: fi.Size(), Total: total})
+			}
+		})
+	}
+}
+
+// sendProgress delivers e unless the consumer isn't keeping up
+func sendProgress(events chan<- ProgressEvent, e ProgressEvent) {
+	select {
+	case events <- e:
+	default:
+	}
+}
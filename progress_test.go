@@ -0,0 +1,132 @@
+package gobrew
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestInstallWithProgress(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	serveReleases(t, &gb, hostReleases("1.16"))
+	gb.registryPath = serveArchives(t, gb, "1.16").URL + "/"
+	events := make(chan ProgressEvent, 100)
+
+	if err := gb.InstallWithProgress("go1.16", events); err != nil {
+		t.Fatal(err)
+	}
+
+	var stages []ProgressStage
+	var last ProgressEvent
+	files := 0
+	for e := range events {
+		if len(stages) == 0 || stages[len(stages)-1] != e.Stage {
+			stages = append(stages, e.Stage)
+		}
+		if e.Version != "1.16" {
+			t.Errorf("unexpected version in %+v", e)
+		}
+		if e.Stage == StageExtracting && e.Files > files {
+			files = e.Files
+		}
+		last = e
+	}
+	want := []ProgressStage{StageDownloading, StageExtracting, StageVerifying, StageDone}
+	if len(stages) != len(want) {
+		t.Fatalf("got stages %v, want %v", stages, want)
+	}
+	for i := range want {
+		if stages[i] != want[i] {
+			t.Fatalf("got stages %v, want %v", stages, want)
+		}
+	}
+	if files != 2 {
+		t.Errorf("expected the 2 files of the archive counted, got %d", files)
+	}
+	if last.Err != nil {
+		t.Errorf("expected no error on done, got %s", last.Err)
+	}
+	if !gb.existsVersion("1.16") {
+		t.Error("expected 1.16 installed")
+	}
+}
+
+func TestInstallWithProgressFailure(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	gb.registryPath = serveArchives(t, gb).URL + "/"
+	events := make(chan ProgressEvent, 100)
+
+	err := gb.InstallWithProgress("1.16", events)
+	if err == nil {
+		t.Fatal("expected an error for a version the registry doesn't have")
+	}
+	var last ProgressEvent
+	for e := range events {
+		last = e
+	}
+	if last.Stage != StageDone || last.Err == nil {
+		t.Errorf("expected a done event with the error, got %+v", last)
+	}
+	if _, err := os.Stat(gb.getVersionDir("1.16")); !os.IsNotExist(err) {
+		t.Error("expected the failed install cleaned up")
+	}
+}
+
+func TestInstallWithProgressSlowConsumer(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	gb.registryPath = serveArchives(t, gb, "1.16").URL + "/"
+	// nobody reads, sending must not block the install
+	events := make(chan ProgressEvent)
+
+	if err := gb.InstallWithProgress("1.16", events); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := <-events; ok {
+		t.Error("expected the channel closed")
+	}
+}
+
+func TestInstallWithProgressReusesCachedArchive(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	gb.EnableKeepDownloads()
+	dir := tempDir(t)
+	name := gb.archiveName("1.16")
+	writeTarGz(t, filepath.Join(dir, name), map[string]string{
+		"go/bin/go":    "#!/bin/sh\necho 'go version go1.16 " + strings.Replace(gb.getArch(), "-", "/", 1) + "'\n",
+		"go/bin/gofmt": "#!/bin/sh\n",
+	})
+	sum, err := fileSHA256(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := json.Marshal([]Release{{Version: "go1.16", Stable: true, Files: []Build{{
+		Filename: name, OS: runtime.GOOS, Arch: archiveArch(runtime.GOOS, runtime.GOARCH), Kind: "archive", SHA256: sum,
+	}}}})
+	serveReleases(t, &gb, string(body))
+	downloads := 0
+	files := http.FileServer(http.Dir(dir))
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downloads++
+		files.ServeHTTP(w, r)
+	}))
+	defer registry.Close()
+	gb.registryPath = registry.URL + "/"
+
+	for i := 0; i < 2; i++ {
+		if err := gb.InstallWithProgress("1.16", make(chan ProgressEvent, 100)); err != nil {
+			t.Fatal(err)
+		}
+		if !gb.existsVersion("1.16") {
+			t.Fatal("expected 1.16 installed")
+		}
+		gb.cleanVersionDir("1.16")
+	}
+	if downloads != 1 {
+		t.Errorf("expected the kept archive reused like Install does, got %d downloads", downloads)
+	}
+}
@@ -0,0 +1,89 @@
+package gobrew
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/kevincobain2000/gobrew/utils"
+)
+
+const protectedFile = "protected.json"
+
+// ListProtected returns the versions marked with Protect, oldest first
+func (gb *GoBrew) ListProtected() ([]string, error) {
+	protected := make([]string, 0)
+	content, err := ioutil.ReadFile(filepath.Join(gb.installDir, protectedFile))
+	if os.IsNotExist(err) {
+		return protected, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, &protected); err != nil {
+		return nil, fmt.Errorf("%s is corrupt: %s", protectedFile, err)
+	}
+	return protected, nil
+}
+
+// Protect marks an installed version so Prune never removes it
+func (gb *GoBrew) Protect(version string) error {
+	version = gb.versionName(version)
+	if !gb.existsVersion(version) {
+		return fmt.Errorf("version %s is not installed", version)
+	}
+	protected, err := gb.ListProtected()
+	if err != nil {
+		return err
+	}
+	if utils.Find(protected, version) {
+		return nil
+	}
+	return gb.writeProtected(append(protected, version))
+}
+
+// Unprotect lets Prune remove version again
+func (gb *GoBrew) Unprotect(version string) error {
+	version = gb.versionName(version)
+	protected, err := gb.ListProtected()
+	if err != nil {
+		return err
+	}
+	kept := make([]string, 0, len(protected))
+	for _, p := range protected {
+		if p != version {
+			kept = append(kept, p)
+		}
+	}
+	if len(kept) == len(protected) {
+		return fmt.Errorf("version %s is not protected", version)
+	}
+	return gb.writeProtected(kept)
+}
+
+func (gb *GoBrew) writeProtected(protected []string) error {
+	sortVersions(protected)
+	content, err := json.MarshalIndent(protected, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(gb.installDir, os.ModePerm); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(gb.installDir, protectedFile), content, 0644)
+}
+
+// protectedSet returns the protected versions for lookups
+func (gb *GoBrew) protectedSet() (map[string]bool, error) {
+	protected, err := gb.ListProtected()
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[string]bool, len(protected))
+	for _, version := range protected {
+		set[version] = true
+	}
+	return set, nil
+}
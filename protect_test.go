@@ -0,0 +1,57 @@
+package gobrew
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProtect(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	for _, v := range []string{"1.15", "1.16", "1.17", "1.18"} {
+		installSizedVersion(t, gb, v, 10)
+	}
+	useFixture(t, gb, "1.18")
+
+	if err := gb.Protect("1.99"); err == nil {
+		t.Error("expected an error protecting a version that is not installed")
+	}
+	for _, v := range []string{"1.16", "go1.15", "1.16"} {
+		if err := gb.Protect(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	protected, err := gb.ListProtected()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(protected, []string{"1.15", "1.16"}) {
+		t.Errorf("expected 1.15 and 1.16 protected, got %v", protected)
+	}
+
+	result, err := gb.Prune(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result.Versions, []string{"1.17"}) {
+		t.Errorf("expected only 1.17 pruned, got %v", result.Versions)
+	}
+	for _, v := range []string{"1.15", "1.16", "1.18"} {
+		if !gb.existsVersion(v) {
+			t.Errorf("expected %s left intact", v)
+		}
+	}
+
+	if err := gb.Unprotect("1.15"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gb.Unprotect("1.15"); err == nil {
+		t.Error("expected an error unprotecting a version that is not protected")
+	}
+	result, err = gb.Prune(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result.Versions, []string{"1.15"}) {
+		t.Errorf("expected 1.15 pruned once unprotected, got %v", result.Versions)
+	}
+}
@@ -0,0 +1,76 @@
+package gobrew
+
+import (
+	"fmt"
+
+	"github.com/kevincobain2000/gobrew/utils"
+)
+
+// PruneResult lists the versions a prune removed, or would remove on a dry
+// run, and the space they take
+type PruneResult struct {
+	Versions  []string
+	Reclaimed int64
+}
+
+// Prune removes every installed version but the current and protected ones.
+// A dry run only reports what would be removed.
+func (gb *GoBrew) Prune(dryRun bool) (PruneResult, error) {
+	return gb.PruneKeep(0, dryRun)
+}
+
+// PruneKeep removes installed versions except the current one, the protected
+// ones and the keep newest others, along with the aliases and default pointing
+// at them. A dry run only reports what would be removed.
+func (gb *GoBrew) PruneKeep(keep int, dryRun bool) (PruneResult, error) {
+	result := PruneResult{Versions: make([]string, 0)}
+	if keep < 0 {
+		return result, fmt.Errorf("cannot keep %d versions, keep must not be negative", keep)
+	}
+
+	versions, err := gb.InstalledVersions()
+	if err != nil {
+		return result, err
+	}
+	sortVersions(versions)
+	cv := gb.CurrentVersion()
+	protected, err := gb.protectedSet()
+	if err != nil {
+		return result, err
+	}
+
+	candidates := make([]string, 0, len(versions))
+	for _, version := range versions {
+		// the system root is read-only
+		if version != cv && !protected[version] && !gb.isSystemVersion(version) {
+			candidates = append(candidates, version)
+		}
+	}
+	if keep > len(candidates) {
+		keep = len(candidates)
+	}
+	candidates = candidates[:len(candidates)-keep]
+
+	for _, version := range candidates {
+		size, err := utils.DirSize(gb.getVersionDir(version))
+		if err != nil {
+			return result, err
+		}
+		if dryRun {
+			utils.ColorInfo.Fprintf(gb.stdout, "[Info] Would remove version: %s (%s)\n", version, utils.HumanSize(size))
+		} else {
+			gb.cleanVersionDir(version)
+			utils.ColorSuccess.Fprintf(gb.stdout, "[Success] Version: %s uninstalled (%s)\n", version, utils.HumanSize(size))
+			gb.dropReferences(version)
+		}
+		result.Versions = append(result.Versions, version)
+		result.Reclaimed += size
+	}
+
+	if dryRun {
+		utils.ColorInfo.Fprintf(gb.stdout, "[Info] Would reclaim %s\n", utils.HumanSize(result.Reclaimed))
+	} else {
+		utils.ColorSuccess.Fprintf(gb.stdout, "[Success] Reclaimed %s\n", utils.HumanSize(result.Reclaimed))
+	}
+	return result, nil
+}
@@ -0,0 +1,135 @@
+package gobrew
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// installSizedVersion installs version with a single file of size bytes
+func installSizedVersion(t *testing.T, gb GoBrew, version string, size int) {
+	t.Helper()
+	binDir := filepath.Join(gb.getVersionDir(version), "go", "bin")
+	if err := os.MkdirAll(binDir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(binDir, "go"), make([]byte, size), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// useFixture points the current symlinks at version
+func useFixture(t *testing.T, gb GoBrew, version string) {
+	t.Helper()
+	os.MkdirAll(gb.currentDir, os.ModePerm)
+	os.Remove(gb.currentBinDir)
+	os.Remove(gb.currentGoDir)
+	if err := os.Symlink(filepath.Join(gb.getVersionDir(version), "go", "bin"), gb.currentBinDir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(gb.getVersionDir(version), "go"), gb.currentGoDir); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPruneDryRun(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	installSizedVersion(t, gb, "1.15", 1000)
+	installSizedVersion(t, gb, "1.16", 2000)
+	installSizedVersion(t, gb, "1.17", 4000)
+	useFixture(t, gb, "1.17")
+	var buf bytes.Buffer
+	gb.stdout = &buf
+
+	result, err := gb.Prune(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result.Versions, []string{"1.15", "1.16"}) || result.Reclaimed != 3000 {
+		t.Errorf("unexpected result %+v", result)
+	}
+	for _, v := range []string{"1.15", "1.16", "1.17"} {
+		if !gb.existsVersion(v) {
+			t.Errorf("dry run removed %s", v)
+		}
+	}
+	if !strings.Contains(buf.String(), "Would reclaim 2.9 KB") {
+		t.Errorf("missing summary in %q", buf.String())
+	}
+}
+
+func TestPruneKeep(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	for _, v := range []string{"1.9", "1.10", "1.16", "1.17"} {
+		installSizedVersion(t, gb, v, 10)
+	}
+	useFixture(t, gb, "1.9")
+
+	result, err := gb.PruneKeep(1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result.Versions, []string{"1.10", "1.16"}) {
+		t.Errorf("unexpected pruned versions %v", result.Versions)
+	}
+	installed, _ := gb.InstalledVersions()
+	if !reflect.DeepEqual(installed, []string{"1.9", "1.17"}) {
+		t.Errorf("unexpected remaining versions %v", installed)
+	}
+}
+
+func TestPruneKeepNegative(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	for _, v := range []string{"1.16", "1.17"} {
+		installSizedVersion(t, gb, v, 10)
+	}
+	useFixture(t, gb, "1.17")
+
+	if _, err := gb.PruneKeep(-1, false); err == nil {
+		t.Fatal("expected an error for a negative keep")
+	}
+	installed, _ := gb.InstalledVersions()
+	if !reflect.DeepEqual(installed, []string{"1.16", "1.17"}) {
+		t.Errorf("expected nothing removed, got %v", installed)
+	}
+
+	empty := newTestGoBrew(tempDir(t))
+	if _, err := empty.PruneKeep(-1, false); err == nil {
+		t.Error("expected an error for a negative keep on an empty root")
+	}
+}
+
+func TestPruneCleansReferences(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	for _, v := range []string{"1.16", "1.17"} {
+		installSizedVersion(t, gb, v, 10)
+	}
+	useFixture(t, gb, "1.17")
+	if err := gb.Alias("old", "1.16"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gb.Alias("new", "1.17"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gb.SetDefault("1.16"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := gb.Prune(false); err != nil {
+		t.Fatal(err)
+	}
+	aliases, err := gb.Aliases()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(aliases, map[string]string{"new": "1.17"}) {
+		t.Errorf("expected only the alias of the kept version left, got %v", aliases)
+	}
+	if dv, _ := gb.DefaultVersion(); dv != "" {
+		t.Errorf("expected the default pointing at a pruned version removed, got %q", dv)
+	}
+}
@@ -0,0 +1,68 @@
+package gobrew
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/kevincobain2000/gobrew/utils"
+)
+
+// ReleaseDate of version, from the JSON API when it publishes one, else the
+// date of its tag in the go repository
+func (gb *GoBrew) ReleaseDate(version string) (time.Time, error) {
+	if normalized, err := normalizeVersion(version); err == nil {
+		version = normalized
+	}
+	releases, err := gb.fetchReleases()
+	if err != nil {
+		gb.debug.Printf("no release date from the JSON API: %s", err)
+	} else if release, ok := findRelease(releases, version); ok && !release.Date.IsZero() {
+		return release.Date, nil
+	}
+	return gb.tagDate(version)
+}
+
+// tagDate fetches nothing but the tag of version to read its date
+func (gb *GoBrew) tagDate(version string) (time.Time, error) {
+	if gb.offline {
+		return time.Time{}, ErrOfflineMode
+	}
+	dir, err := ioutil.TempDir("", "gobrew-tag")
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer os.RemoveAll(dir)
+	ctx, cancel := context.WithTimeout(context.Background(), gb.gitTimeout)
+	defer cancel()
+
+	tag := "refs/tags/go" + version
+	if _, err := gb.git(ctx, "init", "--bare", "--quiet", dir); err != nil {
+		return time.Time{}, err
+	}
+	if _, err := gb.git(ctx, "-C", dir, "fetch", "--quiet", "--depth=1", "--filter=tree:0", gb.tagsRepo, "+"+tag+":"+tag); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return time.Time{}, fmt.Errorf("%w: fetching tag go%s took over %s", ErrTimeout, version, gb.gitTimeout)
+		}
+		return time.Time{}, fmt.Errorf("version %s has no tag: %s", version, err)
+	}
+	output, err := gb.git(ctx, "-C", dir, "for-each-ref", "--format=%(creatordate:iso-strict)", tag)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339, strings.TrimSpace(output))
+}
+
+func (gb *GoBrew) git(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	gb.debug.Printf("running %s", strings.Join(cmd.Args, " "))
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%s: %s", err, strings.TrimSpace(utils.BytesToString(output)))
+	}
+	return utils.BytesToString(output), nil
+}
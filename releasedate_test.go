@@ -0,0 +1,44 @@
+package gobrew
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReleaseDateFromAPI(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	serveReleases(t, &gb, `[
+  {"version": "go1.17.6", "stable": true, "date": "2022-01-06T19:00:00Z", "files": []},
+  {"version": "go1.16.13", "stable": true, "files": []}
+]`)
+	// the fallback must not be needed
+	fakeBin(t, "git", "exit 1\n")
+
+	date, err := gb.ReleaseDate("go1.17.6")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2022, 1, 6, 19, 0, 0, 0, time.UTC); !date.Equal(want) {
+		t.Errorf("expected %s, got %s", want, date)
+	}
+	if _, err := gb.ReleaseDate("1.16.13"); err == nil {
+		t.Error("expected an error without a date nor a tag")
+	}
+}
+
+func TestReleaseDateFromTag(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	serveReleases(t, &gb, `[{"version": "go1.16.13", "stable": true, "files": []}]`)
+	fakeBin(t, "git", `for arg in "$@"; do
+	case "$arg" in for-each-ref) echo 2021-12-09T20:02:52+00:00;; esac
+done
+`)
+
+	date, err := gb.ReleaseDate("1.16.13")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2021, 12, 9, 20, 2, 52, 0, time.UTC); !date.Equal(want) {
+		t.Errorf("expected %s, got %s", want, date)
+	}
+}
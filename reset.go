@@ -0,0 +1,57 @@
+package gobrew
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Reset recovers from broken state while keeping every installed version: it
+// removes the current symlinks, the downloads, unfinished extractions and
+// dangling symlinks in versionsDir, leaving no version selected
+func (gb *GoBrew) Reset() error {
+	err := gb.withCurrentLock(func() error { return os.RemoveAll(gb.currentDir) })
+	if err != nil {
+		return err
+	}
+	gb.cleanDownloadsDir()
+	if gb.tmpDir != "" {
+		leftovers, _ := filepath.Glob(filepath.Join(gb.tmpDir, ".tmp-*"))
+		for _, leftover := range leftovers {
+			os.RemoveAll(leftover)
+		}
+	}
+
+	entries, err := ioutil.ReadDir(gb.versionsDir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		path := filepath.Join(gb.versionsDir, entry.Name())
+		if strings.HasPrefix(entry.Name(), ".tmp-") {
+			if err := os.RemoveAll(path); err != nil {
+				return err
+			}
+			continue
+		}
+		// external versions are a directory with a go symlink
+		if entry.IsDir() {
+			path = filepath.Join(path, "go")
+			if fi, err := os.Lstat(path); err != nil || fi.Mode()&os.ModeSymlink == 0 {
+				continue
+			}
+		} else if entry.Mode()&os.ModeSymlink == 0 {
+			continue
+		}
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			if err := os.RemoveAll(filepath.Join(gb.versionsDir, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
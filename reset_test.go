@@ -0,0 +1,41 @@
+package gobrew
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReset(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	installSizedVersion(t, gb, "1.16", 10)
+	installSizedVersion(t, gb, "1.17", 10)
+	useFixture(t, gb, "1.17")
+
+	// break the current symlinks and leave debris behind
+	os.Remove(gb.currentBinDir)
+	os.Symlink(filepath.Join(gb.versionsDir, "1.99", "go", "bin"), gb.currentBinDir)
+	os.Symlink(filepath.Join(tempDir(t), "gone"), filepath.Join(gb.versionsDir, "external"))
+	os.MkdirAll(filepath.Join(gb.versionsDir, "external-gone"), os.ModePerm)
+	os.Symlink(filepath.Join(tempDir(t), "gone"), filepath.Join(gb.versionsDir, "external-gone", "go"))
+	os.MkdirAll(filepath.Join(gb.versionsDir, ".tmp-1.18", "go"), os.ModePerm)
+	writeTarGz(t, filepath.Join(gb.downloadsDir, gb.archiveName("1.18")), map[string]string{"go/bin/go": "go"})
+
+	if err := gb.Reset(); err != nil {
+		t.Fatal(err)
+	}
+
+	if cv := gb.CurrentVersion(); cv != "" {
+		t.Errorf("expected no current version, got %q", cv)
+	}
+	for _, path := range []string{gb.currentDir, gb.downloadsDir, filepath.Join(gb.versionsDir, "external"), filepath.Join(gb.versionsDir, "external-gone"), filepath.Join(gb.versionsDir, ".tmp-1.18")} {
+		if _, err := os.Lstat(path); !os.IsNotExist(err) {
+			t.Errorf("expected %s removed, got %v", path, err)
+		}
+	}
+	installed, _ := gb.InstalledVersions()
+	if !reflect.DeepEqual(installed, []string{"1.16", "1.17"}) {
+		t.Errorf("expected installed versions kept, got %v", installed)
+	}
+}
@@ -0,0 +1,131 @@
+package gobrew
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/kevincobain2000/gobrew/utils"
+)
+
+// seedWorkers versions are downloaded concurrently while seeding a mirror
+const seedWorkers = 4
+
+// seedAttempts per version before seeding gives up on it
+const seedAttempts = 3
+
+// SeedMirror downloads the archive of each version for this platform and its
+// .sha256 into destDir, the layout GOBREW_REGISTRY expects, verifying every
+// archive against its checksum. Nothing is extracted or installed, and
+// archives already seeded intact are kept
+func (gb *GoBrew) SeedMirror(versions []string, destDir string) error {
+	if len(versions) == 0 {
+		return fmt.Errorf("no version provided")
+	}
+	normalized := make([]string, 0, len(versions))
+	for _, version := range versions {
+		version, err := normalizeVersion(version)
+		if err != nil {
+			return err
+		}
+		normalized = append(normalized, version)
+	}
+	if err := os.MkdirAll(destDir, os.ModePerm); err != nil {
+		return err
+	}
+
+	errs := make([]error, len(normalized))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < seedWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				errs[i] = gb.seedVersion(normalized[i], destDir)
+			}
+		}()
+	}
+	for i := range normalized {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	failed := 0
+	for i, err := range errs {
+		if err != nil {
+			utils.ColorError.Fprintf(gb.stderr, "[Error] Seeding version: %s: %s\n", normalized[i], err)
+			failed++
+			continue
+		}
+		utils.ColorSuccess.Fprintf(gb.stdout, "[Success] Seeded version: %s\n", normalized[i])
+	}
+	if failed > 0 {
+		return fmt.Errorf("seeding %d of %d versions failed", failed, len(normalized))
+	}
+	return nil
+}
+
+// seedVersion downloads and verifies version, retrying up to seedAttempts times
+func (gb *GoBrew) seedVersion(version string, destDir string) error {
+	archive := filepath.Join(destDir, gb.archiveName(version))
+	if expected, err := readChecksumFile(archive + ".sha256"); err == nil && verifyChecksum(archive, expected) == nil {
+		gb.debug.Printf("%s is already seeded", archive)
+		return nil
+	}
+
+	var err error
+	for attempt := 1; attempt <= seedAttempts; attempt++ {
+		if err = gb.seedArchive(version, archive); err == nil || errors.Is(err, ErrOfflineMode) {
+			return err
+		}
+		gb.debug.Printf("seeding %s, attempt %d of %d: %s", version, attempt, seedAttempts, err)
+	}
+	return err
+}
+
+// seedArchive downloads the checksum and archive of version next to their
+// final names, only renaming them into place once the archive verifies
+func (gb *GoBrew) seedArchive(version string, archive string) error {
+	url := gb.archiveURL(version)
+	sumFile := archive + ".sha256"
+	defer os.Remove(sumFile + ".part")
+	defer os.Remove(archive + ".part")
+
+	if err := gb.download(url+".sha256", sumFile+".part"); err != nil {
+		return err
+	}
+	expected, err := readChecksumFile(sumFile + ".part")
+	if err != nil {
+		return err
+	}
+	if err := gb.download(url, archive+".part"); err != nil {
+		return err
+	}
+	if err := verifyChecksum(archive+".part", expected); err != nil {
+		return err
+	}
+	if err := os.Rename(archive+".part", archive); err != nil {
+		return err
+	}
+	return os.Rename(sumFile+".part", sumFile)
+}
+
+// readChecksumFile returns the sha256 of a .sha256 file, which holds the hex
+// digest optionally followed by the file name
+func readChecksumFile(path string) (string, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Fields(utils.BytesToString(content))
+	if len(fields) == 0 || len(fields[0]) != 64 {
+		return "", fmt.Errorf("%s is not a sha256 checksum file", filepath.Base(path))
+	}
+	return fields[0], nil
+}
@@ -0,0 +1,93 @@
+package gobrew
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// serveMirror serves an archive and its .sha256 for each version, the first
+// download of every archive corrupted when flaky
+func serveMirror(t *testing.T, gb GoBrew, flaky bool, versions ...string) *httptest.Server {
+	t.Helper()
+	dir := tempDir(t)
+	for _, version := range versions {
+		archive := filepath.Join(dir, gb.archiveName(version))
+		writeTarGz(t, archive, map[string]string{"go/bin/go": version})
+		sum, err := fileSHA256(archive)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ioutil.WriteFile(archive+".sha256", []byte(sum+"  "+filepath.Base(archive)+"\n"), 0644)
+	}
+
+	var mu sync.Mutex
+	served := make(map[string]bool)
+	files := http.FileServer(http.Dir(dir))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		first := !served[r.URL.Path]
+		served[r.URL.Path] = true
+		mu.Unlock()
+		if flaky && first && strings.HasSuffix(r.URL.Path, ".tar.gz") {
+			w.Write([]byte("truncated"))
+			return
+		}
+		files.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSeedMirror(t *testing.T) {
+	for _, flaky := range []bool{false, true} {
+		gb := newTestGoBrew(tempDir(t))
+		gb.registryPath = serveMirror(t, gb, flaky, "1.16", "1.17").URL + "/"
+		dest := filepath.Join(tempDir(t), "mirror")
+
+		if err := gb.SeedMirror([]string{"1.16", "go1.17"}, dest); err != nil {
+			t.Fatalf("flaky %v: %s", flaky, err)
+		}
+
+		for _, version := range []string{"1.16", "1.17"} {
+			archive := filepath.Join(dest, gb.archiveName(version))
+			expected, err := readChecksumFile(archive + ".sha256")
+			if err != nil {
+				t.Fatalf("flaky %v: %s", flaky, err)
+			}
+			if err := verifyChecksum(archive, expected); err != nil {
+				t.Errorf("flaky %v: %s", flaky, err)
+			}
+		}
+		if leftovers, _ := filepath.Glob(filepath.Join(dest, "*.part")); len(leftovers) > 0 {
+			t.Errorf("flaky %v: unexpected partial downloads %v", flaky, leftovers)
+		}
+		if _, err := os.Stat(gb.versionsDir); !os.IsNotExist(err) {
+			t.Errorf("flaky %v: seeding should not install anything", flaky)
+		}
+	}
+}
+
+func TestSeedMirrorChecksumMismatch(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	source := tempDir(t)
+	archive := filepath.Join(source, gb.archiveName("1.16"))
+	writeTarGz(t, archive, map[string]string{"go/bin/go": "1.16"})
+	ioutil.WriteFile(archive+".sha256", []byte(strings.Repeat("0", 64)+"\n"), 0644)
+	server := httptest.NewServer(http.FileServer(http.Dir(source)))
+	defer server.Close()
+	gb.registryPath = server.URL + "/"
+	dest := filepath.Join(tempDir(t), "mirror")
+
+	if err := gb.SeedMirror([]string{"1.16"}, dest); err == nil {
+		t.Fatal("expected seeding to fail on a checksum mismatch")
+	}
+	if entries, _ := ioutil.ReadDir(dest); len(entries) > 0 {
+		t.Errorf("expected nothing seeded, got %d files", len(entries))
+	}
+}
@@ -0,0 +1,107 @@
+package gobrew
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kevincobain2000/gobrew/utils"
+)
+
+// shellRCFiles of each supported shell relative to home, the first existing
+// one is edited, or the first one is created
+var shellRCFiles = map[string][]string{
+	"bash": {".bashrc", ".bash_profile"},
+	"zsh":  {".zshrc"},
+	"fish": {filepath.Join(".config", "fish", "config.fish")},
+}
+
+// shellRC returns the rc file of the shell in $SHELL and that shell
+func (gb *GoBrew) shellRC() (string, string, error) {
+	shell := filepath.Base(os.Getenv("SHELL"))
+	candidates, ok := shellRCFiles[shell]
+	if !ok {
+		return "", shell, fmt.Errorf("unsupported shell %q, add %s to PATH by hand", shell, gb.currentBinDir)
+	}
+	for _, candidate := range candidates {
+		path := filepath.Join(gb.homeDir, candidate)
+		if _, err := os.Stat(path); err == nil {
+			return path, shell, nil
+		}
+	}
+	return filepath.Join(gb.homeDir, candidates[0]), shell, nil
+}
+
+// pathExport puts currentBinDir first on PATH in the syntax of shell
+func (gb *GoBrew) pathExport(shell string) string {
+	if shell == "fish" {
+		return fmt.Sprintf("set -gx PATH %q $PATH", gb.currentBinDir)
+	}
+	return fmt.Sprintf("export PATH=%q", gb.currentBinDir+":$PATH")
+}
+
+// EnsurePath adds currentBinDir to PATH in the rc file of the user's shell
+// unless it already mentions it or `gobrew shellenv`. The rc file is backed
+// up to <rc>.gobrew.bak before it is edited
+func (gb *GoBrew) EnsurePath() error {
+	rc, shell, err := gb.shellRC()
+	if err != nil {
+		return err
+	}
+	content, err := ioutil.ReadFile(rc)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if text := utils.BytesToString(content); strings.Contains(text, gb.currentBinDir) || strings.Contains(text, "gobrew shellenv") {
+		utils.ColorInfo.Fprintf(gb.stdout, "[Info] %s is already on PATH in %s\n", gb.currentBinDir, rc)
+		return nil
+	}
+
+	if err == nil {
+		if err := ioutil.WriteFile(rc+".gobrew.bak", content, 0644); err != nil {
+			return fmt.Errorf("backing up %s: %w", rc, err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(rc), os.ModePerm); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(rc, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(f, "\n# added by gobrew\n%s\n", gb.pathExport(shell)); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	utils.ColorSuccess.Fprintf(gb.stdout, "[Success] Added %s to PATH in %s, open a new shell to use it\n", gb.currentBinDir, rc)
+	return nil
+}
+
+// cdHook wraps cd to run `gobrew use-auto` on entering a directory with a
+// .go-version, it is valid bash and zsh
+const cdHook = `_gobrew_hook() {
+  if [ -f %[1]s ]; then
+    gobrew use-auto
+  fi
+}
+
+cd() {
+  builtin cd "$@" || return
+  _gobrew_hook
+}
+`
+
+// HookCommand returns the shell code switching versions automatically on cd,
+// for `eval "$(gobrew hook bash)"` in the rc file of bash or zsh
+func HookCommand(shell string) (string, error) {
+	switch filepath.Base(shell) {
+	case "bash", "zsh":
+		return fmt.Sprintf(cdHook, goVersionFile), nil
+	}
+	return "", fmt.Errorf("unsupported shell %q, use bash or zsh", shell)
+}
@@ -0,0 +1,106 @@
+package gobrew
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEnsurePath(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	defer os.Setenv("SHELL", os.Getenv("SHELL"))
+	os.Setenv("SHELL", "/bin/zsh")
+	rc := filepath.Join(gb.homeDir, ".zshrc")
+	original := "alias ll='ls -l'\n"
+	ioutil.WriteFile(rc, []byte(original), 0644)
+
+	for i := 0; i < 2; i++ {
+		if err := gb.EnsurePath(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	content, _ := ioutil.ReadFile(rc)
+	if n := strings.Count(string(content), gb.currentBinDir); n != 1 {
+		t.Errorf("expected the export added once, found %d times in:\n%s", n, content)
+	}
+	if !strings.HasPrefix(string(content), original) {
+		t.Errorf("expected the existing content kept, got:\n%s", content)
+	}
+	if backup, err := ioutil.ReadFile(rc + ".gobrew.bak"); err != nil || string(backup) != original {
+		t.Errorf("expected a backup of the original rc file, got %q, %v", backup, err)
+	}
+}
+
+func TestEnsurePathShells(t *testing.T) {
+	defer os.Setenv("SHELL", os.Getenv("SHELL"))
+	tests := []struct {
+		shell string
+		rc    string
+		line  string
+	}{
+		{shell: "/bin/bash", rc: ".bashrc", line: "export PATH="},
+		{shell: "/usr/local/bin/fish", rc: filepath.Join(".config", "fish", "config.fish"), line: "set -gx PATH"},
+	}
+	for _, tt := range tests {
+		gb := newTestGoBrew(tempDir(t))
+		os.Setenv("SHELL", tt.shell)
+
+		if err := gb.EnsurePath(); err != nil {
+			t.Fatalf("%s: %s", tt.shell, err)
+		}
+		content, err := ioutil.ReadFile(filepath.Join(gb.homeDir, tt.rc))
+		if err != nil {
+			t.Fatalf("%s: %s", tt.shell, err)
+		}
+		if !strings.Contains(string(content), tt.line) || !strings.Contains(string(content), gb.currentBinDir) {
+			t.Errorf("%s: unexpected rc file:\n%s", tt.shell, content)
+		}
+	}
+
+	os.Setenv("SHELL", "/bin/tcsh")
+	gb := newTestGoBrew(tempDir(t))
+	if err := gb.EnsurePath(); err == nil {
+		t.Error("expected an error for an unsupported shell")
+	}
+}
+
+func TestHookCommand(t *testing.T) {
+	for _, shell := range []string{"bash", "/bin/zsh"} {
+		hook, err := HookCommand(shell)
+		if err != nil {
+			t.Fatalf("%s: %s", shell, err)
+		}
+		for _, want := range []string{goVersionFile, "gobrew use-auto", "builtin cd"} {
+			if !strings.Contains(hook, want) {
+				t.Errorf("%s: expected %q in the hook:\n%s", shell, want, hook)
+			}
+		}
+	}
+	if _, err := HookCommand("fish"); err == nil {
+		t.Error("expected an error for an unsupported shell")
+	}
+}
+
+func TestHookCommandRuns(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash is not installed")
+	}
+	fakeBin(t, "gobrew", "echo \"gobrew $*\"\n")
+	hook, _ := HookCommand("bash")
+	project := tempDir(t)
+	ioutil.WriteFile(filepath.Join(project, goVersionFile), []byte("1.16\n"), 0644)
+	other := tempDir(t)
+
+	out, err := exec.Command(bash, "-c", hook+"cd "+other+"\ncd "+project+"\n").CombinedOutput()
+	if err != nil {
+		t.Fatalf("%s: %s", err, out)
+	}
+	if got := strings.TrimSpace(string(out)); got != "gobrew use-auto" {
+		t.Errorf("expected use-auto only in the project, got %q", got)
+	}
+}
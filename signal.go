@@ -0,0 +1,51 @@
+package gobrew
+
+import (
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/kevincobain2000/gobrew/utils"
+)
+
+// osExit is swapped out by tests
+var osExit = os.Exit
+
+// EnableSignalCleanup makes Install catch SIGINT and SIGTERM to remove the
+// partially installed version and its download before exiting. It is off by
+// default so embedding programs keep control of their signals.
+func (gb *GoBrew) EnableSignalCleanup() {
+	gb.cleanupOnSignal = true
+}
+
+// watchSignals cleans up version if the process is interrupted before the
+// returned stop func is called
+func (gb *GoBrew) watchSignals(version string) (stop func()) {
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case sig := <-signals:
+			utils.ColorError.Fprintf(gb.stderr, "\n[Error] Interrupted by %s, removing partial install of %s\n", sig, version)
+			gb.cleanupPartialInstall(version)
+			osExit(130)
+		case <-done:
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
+
+// cleanupPartialInstall removes the version directory, the extraction in
+// progress and the download of an install that didn't finish
+func (gb *GoBrew) cleanupPartialInstall(version string) {
+	gb.cleanVersionDir(version)
+	os.RemoveAll(gb.extractTmpDir(version))
+	os.Remove(filepath.Join(gb.downloadsDir, gb.archiveName(version)))
+}
@@ -0,0 +1,50 @@
+//go:build !windows
+// +build !windows
+
+package gobrew
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestWatchSignalsCleansPartialInstall(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	if err := gb.mkdirs("1.16"); err != nil {
+		t.Fatal(err)
+	}
+	archive := filepath.Join(gb.downloadsDir, gb.archiveName("1.16"))
+	writeTarGz(t, archive, map[string]string{"go/bin/go": "go"})
+	os.MkdirAll(filepath.Join(gb.getVersionDir("1.16"), "go", "src"), os.ModePerm)
+	tmp := gb.extractTmpDir("1.16")
+	os.MkdirAll(filepath.Join(tmp, "go", "bin"), os.ModePerm)
+
+	exited := make(chan int, 1)
+	osExit = func(code int) { exited <- code }
+	defer func() { osExit = os.Exit }()
+
+	stop := gb.watchSignals("1.16")
+	defer stop()
+	syscall.Kill(os.Getpid(), syscall.SIGINT)
+
+	select {
+	case code := <-exited:
+		if code != 130 {
+			t.Errorf("expected exit code 130, got %d", code)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("signal was not handled")
+	}
+	if _, err := os.Stat(gb.getVersionDir("1.16")); !os.IsNotExist(err) {
+		t.Errorf("expected the partial version dir removed, got %v", err)
+	}
+	if _, err := os.Stat(tmp); !os.IsNotExist(err) {
+		t.Errorf("expected the extraction in progress removed, got %v", err)
+	}
+	if _, err := os.Stat(archive); !os.IsNotExist(err) {
+		t.Errorf("expected the partial download removed, got %v", err)
+	}
+}
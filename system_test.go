@@ -0,0 +1,68 @@
+package gobrew
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSystemRoot(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	system := newTestGoBrew(tempDir(t))
+	gb.systemVersionsDir = system.versionsDir
+
+	installFakeVersion(t, system, "1.16", "go version go1.16 linux/amd64")
+	installFakeVersion(t, system, "1.17", "go version go1.17 linux/amd64")
+	installFakeVersion(t, gb, "1.17", "go version go1.17 linux/amd64")
+	installFakeVersion(t, gb, "1.18beta1", "go version go1.18beta1 linux/amd64")
+
+	installed, err := gb.InstalledVersions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(installed, []string{"1.16", "1.17", "1.18beta1"}) {
+		t.Errorf("unexpected merged listing %v", installed)
+	}
+	if !gb.isSystemVersion("1.16") || gb.isSystemVersion("1.17") {
+		t.Error("1.16 should resolve to the system root, 1.17 to the user root")
+	}
+
+	os.MkdirAll(gb.currentDir, os.ModePerm)
+	gb.Use("1.16")
+
+	if cv := gb.CurrentVersion(); cv != "1.16" {
+		t.Errorf("expected current version 1.16, got %q", cv)
+	}
+	target, _ := filepath.EvalSymlinks(gb.currentGoDir)
+	want, _ := filepath.EvalSymlinks(filepath.Join(system.getVersionDir("1.16"), "go"))
+	if target != want {
+		t.Errorf("expected current/go to point into the system root, got %s", target)
+	}
+	if _, err := os.Stat(gb.getVersionDir("1.16")); !os.IsNotExist(err) {
+		t.Error("using a system version must not touch the user versions dir")
+	}
+}
+
+func TestSystemRootOnly(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	system := newTestGoBrew(tempDir(t))
+	gb.systemVersionsDir = system.versionsDir
+	installFakeVersion(t, system, "1.16", "go version go1.16 linux/amd64")
+
+	installed, err := gb.InstalledVersions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(installed, []string{"1.16"}) {
+		t.Errorf("expected system versions without a user versions dir, got %v", installed)
+	}
+
+	result, err := gb.Prune(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Versions) != 0 || !gb.existsVersion("1.16") {
+		t.Errorf("prune must not touch the system root, pruned %v", result.Versions)
+	}
+}
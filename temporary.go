@@ -0,0 +1,81 @@
+package gobrew
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+)
+
+// UseTemporary makes version the current version until restore is called,
+// which links the prior current version back, or none if there was none
+func (gb *GoBrew) UseTemporary(version string) (restore func() error, err error) {
+	version = gb.versionName(version)
+	if !gb.existsVersion(version) {
+		return nil, fmt.Errorf("version %s is not installed", version)
+	}
+	if err := os.MkdirAll(gb.currentDir, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	goDir := gb.versionGoroot(version)
+	targets := map[string]string{
+		gb.currentBinDir: gb.versionBinDir(version),
+		gb.currentGoDir:  goDir,
+	}
+	prior := make(map[string]string, len(targets))
+	err = gb.withCurrentLock(func() error {
+		for link := range targets {
+			prior[link], _ = os.Readlink(link)
+		}
+		if err := relink(targets); err != nil {
+			relink(prior)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return func() error {
+		return gb.withCurrentLock(func() error { return relink(prior) })
+	}, nil
+}
+
+// ExecTemporary runs args with version as the current version, for commands
+// relying on the current links. The prior current version is restored when
+// args exit, whether they fail or are interrupted
+func (gb *GoBrew) ExecTemporary(version string, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no command provided")
+	}
+	restore, err := gb.UseTemporary(version)
+	if err != nil {
+		return err
+	}
+	// ^C goes to args, gobrew must live on to restore
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	defer signal.Stop(signals)
+
+	err = gb.Exec(version, args)
+	if restoreErr := restore(); restoreErr != nil && err == nil {
+		err = restoreErr
+	}
+	return err
+}
+
+// relink points each link at its target, removing links with no target
+func relink(targets map[string]string) error {
+	for link, target := range targets {
+		if target == "" {
+			if err := os.RemoveAll(link); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := replaceLink(target, link); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package gobrew
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestExecTemporary(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	installFakeVersion(t, gb, "1.16", "go version go1.16 linux/amd64")
+	installFakeVersion(t, gb, "1.17", "go version go1.17 linux/amd64")
+	useFixture(t, gb, "1.16")
+
+	var buf bytes.Buffer
+	gb.stdout = &buf
+	err := gb.ExecTemporary("1.17", []string{"sh", "-c", "'" + gb.currentBinDir + "/go' version; exit 3"})
+	if err == nil {
+		t.Error("expected the failure of the command returned")
+	}
+	if !strings.Contains(buf.String(), "go1.17") {
+		t.Errorf("expected 1.17 current while the command ran, got %q", buf.String())
+	}
+	if cv := gb.CurrentVersion(); cv != "1.16" {
+		t.Errorf("expected 1.16 restored, got %q", cv)
+	}
+}
+
+func TestUseTemporaryWithoutCurrent(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	installFakeVersion(t, gb, "1.17", "go version go1.17 linux/amd64")
+
+	restore, err := gb.UseTemporary("go1.17")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cv := gb.CurrentVersion(); cv != "1.17" {
+		t.Errorf("expected 1.17 current, got %q", cv)
+	}
+	if err := restore(); err != nil {
+		t.Fatal(err)
+	}
+	for _, link := range []string{gb.currentBinDir, gb.currentGoDir} {
+		if _, err := os.Lstat(link); !os.IsNotExist(err) {
+			t.Errorf("expected %s removed, got %v", link, err)
+		}
+	}
+
+	if _, err := gb.UseTemporary("1.99"); err == nil {
+		t.Error("expected an error for a version that is not installed")
+	}
+}
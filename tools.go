@@ -0,0 +1,77 @@
+package gobrew
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kevincobain2000/gobrew/utils"
+)
+
+const toolsFile = "tools.json"
+
+// Tools returns the packages recorded with RecordTool
+func (gb *GoBrew) Tools() ([]string, error) {
+	var tools []string
+	content, err := ioutil.ReadFile(filepath.Join(gb.installDir, toolsFile))
+	if os.IsNotExist(err) {
+		return tools, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, &tools); err != nil {
+		return nil, fmt.Errorf("%s is corrupt: %s", toolsFile, err)
+	}
+	return tools, nil
+}
+
+// RecordTool remembers pkg, e.g. golang.org/x/tools/gopls@latest, to be
+// reinstalled with go install whenever a version is used
+func (gb *GoBrew) RecordTool(pkg string) error {
+	if pkg == "" {
+		return fmt.Errorf("no package provided")
+	}
+	tools, err := gb.Tools()
+	if err != nil {
+		return err
+	}
+	if utils.Find(tools, pkg) {
+		return nil
+	}
+	content, err := json.MarshalIndent(append(tools, pkg), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(gb.installDir, os.ModePerm); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(gb.installDir, toolsFile), content, 0644)
+}
+
+// ReinstallTools runs go install for every recorded tool with the go of
+// version. It keeps going when one fails and returns the tools that failed
+func (gb *GoBrew) ReinstallTools(version string) error {
+	tools, err := gb.Tools()
+	if err != nil {
+		return err
+	}
+	var failed []string
+	for _, pkg := range tools {
+		if !strings.Contains(pkg, "@") {
+			pkg += "@latest"
+		}
+		utils.ColorInfo.Fprintf(gb.stdout, "[Info] Installing tool: %s \n", pkg)
+		if err := gb.Exec(version, []string{"go", "install", pkg}); err != nil {
+			gb.debug.Printf("go install %s: %s", pkg, err)
+			failed = append(failed, pkg)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("installing tools failed: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
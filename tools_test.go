@@ -0,0 +1,45 @@
+package gobrew
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReinstallTools(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	installFakeVersion(t, gb, "1.17", "go version go1.17 linux/amd64")
+	log := filepath.Join(tempDir(t), "go.log")
+	goBin := filepath.Join(gb.getVersionDir("1.17"), "go", "bin", "go")
+	ioutil.WriteFile(goBin, []byte("#!/bin/sh\necho \"$GOROOT $*\" >> "+log+"\n"), 0755)
+
+	for _, pkg := range []string{"golang.org/x/tools/gopls", "honnef.co/go/tools/cmd/staticcheck@2021.1.2", "golang.org/x/tools/gopls"} {
+		if err := gb.RecordTool(pkg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tools, _ := gb.Tools()
+	if !reflect.DeepEqual(tools, []string{"golang.org/x/tools/gopls", "honnef.co/go/tools/cmd/staticcheck@2021.1.2"}) {
+		t.Errorf("expected each tool recorded once, got %v", tools)
+	}
+
+	if err := gb.ReinstallTools("1.17"); err != nil {
+		t.Fatal(err)
+	}
+	content, _ := ioutil.ReadFile(log)
+	goroot := filepath.Join(gb.getVersionDir("1.17"), "go")
+	want := []string{
+		goroot + " install golang.org/x/tools/gopls@latest",
+		goroot + " install honnef.co/go/tools/cmd/staticcheck@2021.1.2",
+	}
+	if got := strings.Split(strings.TrimSpace(string(content)), "\n"); !reflect.DeepEqual(got, want) {
+		t.Errorf("got commands %q, want %q", got, want)
+	}
+
+	ioutil.WriteFile(goBin, []byte("#!/bin/sh\nexit 1\n"), 0755)
+	if err := gb.ReinstallTools("1.17"); err == nil || !strings.Contains(err.Error(), "staticcheck") {
+		t.Errorf("expected the failed tools reported, got %v", err)
+	}
+}
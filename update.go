@@ -0,0 +1,121 @@
+package gobrew
+
+import (
+	"fmt"
+
+	"github.com/kevincobain2000/gobrew/utils"
+)
+
+// CheckUpdate compares the current version to the latest stable release
+func (gb *GoBrew) CheckUpdate() (current string, latest string, upToDate bool, err error) {
+	current = gb.CurrentVersion()
+	if current == "" {
+		return "", "", false, fmt.Errorf("no current version, use one first")
+	}
+	latest, err = gb.latestStable()
+	if err != nil {
+		return current, "", false, err
+	}
+	return current, latest, compareVersions(current, latest) >= 0, nil
+}
+
+// EnableRemoveSuperseded makes UpgradeAll uninstall the patch each minor line
+// was upgraded from
+func (gb *GoBrew) EnableRemoveSuperseded() {
+	gb.removeSuperseded = true
+}
+
+// UpgradeAll installs the latest released patch of every installed minor line
+// that has a newer one for this platform. A superseded patch that is in use is
+// switched away from before it is removed along with the aliases and default
+// pointing at it, protected ones are kept
+func (gb *GoBrew) UpgradeAll() error {
+	outdated, err := gb.Outdated()
+	if err != nil {
+		return err
+	}
+	protected, err := gb.protectedSet()
+	if err != nil {
+		return err
+	}
+
+	upgraded := make([]string, 0)
+	for _, o := range outdated {
+		gb.Install(o.Latest)
+		upgraded = append(upgraded, fmt.Sprintf("%s -> %s", o.Installed, o.Latest))
+		if !gb.removeSuperseded || gb.isSystemVersion(o.Installed) || protected[o.Installed] {
+			continue
+		}
+		if o.Installed == gb.CurrentVersion() {
+			gb.Use(o.Latest)
+		}
+		gb.cleanVersionDir(o.Installed)
+		utils.ColorSuccess.Fprintf(gb.stdout, "[Success] Version: %s uninstalled\n", o.Installed)
+		gb.dropReferences(o.Installed)
+	}
+
+	if len(upgraded) == 0 {
+		utils.ColorInfo.Fprintln(gb.stdout, "[Info] Every installed minor line is on its latest patch")
+		return nil
+	}
+	for _, upgrade := range upgraded {
+		utils.ColorSuccess.Fprintf(gb.stdout, "[Success] Upgraded %s\n", upgrade)
+	}
+	return nil
+}
+
+// OutdatedInfo is an installed minor line with a newer patch released
+type OutdatedInfo struct {
+	Minor string
+	// Installed is the newest patch of Minor that is installed
+	Installed string
+	Latest    string
+}
+
+// Outdated lists the installed minor lines whose newest installed patch is
+// behind the latest patch released for this platform, oldest line first
+func (gb *GoBrew) Outdated() ([]OutdatedInfo, error) {
+	installed, err := gb.InstalledVersions()
+	if err != nil {
+		return nil, err
+	}
+	remote, err := gb.RemoteVersionsForHost()
+	if err != nil {
+		return nil, err
+	}
+	latest := latestPatches(remote)
+
+	groups := GroupByMinor(installed)
+	delete(groups, otherGroup)
+	minors := make([]string, 0, len(groups))
+	for minor := range groups {
+		minors = append(minors, minor)
+	}
+	sortVersions(minors)
+
+	outdated := make([]OutdatedInfo, 0)
+	for _, minor := range minors {
+		group := groups[minor]
+		newest := group[len(group)-1]
+		patch, ok := latest[minor]
+		if !ok || compareVersions(patch, newest) <= 0 {
+			gb.debug.Printf("%s is the latest patch of %s", newest, minor)
+			continue
+		}
+		outdated = append(outdated, OutdatedInfo{Minor: minor, Installed: newest, Latest: patch})
+	}
+	return outdated, nil
+}
+
+// latestPatches maps each minor line to its newest release, prereleases aside
+func latestPatches(versions []string) map[string]string {
+	latest := make(map[string]string)
+	for minor, group := range GroupByMinor(versions) {
+		for _, version := range group {
+			if v, ok := parseGoVersion(version); ok && v.pre == "" {
+				latest[minor] = version
+			}
+		}
+	}
+	return latest
+}
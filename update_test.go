@@ -0,0 +1,167 @@
+package gobrew
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestCheckUpdate(t *testing.T) {
+	tests := []struct {
+		current      string
+		wantUpToDate bool
+	}{
+		{current: "1.16.13", wantUpToDate: false},
+		{current: "1.17.6", wantUpToDate: true},
+		{current: "1.18beta1", wantUpToDate: true},
+	}
+	for _, tt := range tests {
+		gb := newTestGoBrew(tempDir(t))
+		serveReleases(t, &gb, releasesFixture)
+		installSizedVersion(t, gb, tt.current, 1)
+		useFixture(t, gb, tt.current)
+
+		current, latest, upToDate, err := gb.CheckUpdate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if current != tt.current || latest != "1.17.6" || upToDate != tt.wantUpToDate {
+			t.Errorf("got (%s, %s, %v), want (%s, 1.17.6, %v)", current, latest, upToDate, tt.current, tt.wantUpToDate)
+		}
+	}
+}
+
+func TestCheckUpdateWithoutCurrent(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	if _, _, _, err := gb.CheckUpdate(); err == nil {
+		t.Error("expected an error without a current version")
+	}
+}
+
+// hostReleases mirrors the JSON API with a host archive for each version, newest first
+func hostReleases(versions ...string) string {
+	goos, goarch := runtime.GOOS, archiveArch(runtime.GOOS, runtime.GOARCH)
+	releases := make([]Release, 0, len(versions))
+	for _, version := range versions {
+		v, _ := parseGoVersion(version)
+		releases = append(releases, Release{
+			Version: "go" + version,
+			Stable:  v.pre == "",
+			Files:   []Build{{Filename: "go" + version + "." + goos + "-" + goarch + ".tar.gz", OS: goos, Arch: goarch, Version: "go" + version, Kind: "archive"}},
+		})
+	}
+	body, _ := json.Marshal(releases)
+	return string(body)
+}
+
+func TestUpgradeAll(t *testing.T) {
+	for _, remove := range []bool{false, true} {
+		gb := newTestGoBrew(tempDir(t))
+		serveReleases(t, &gb, hostReleases("1.18beta1", "1.17.6", "1.17.1", "1.16.13", "1.16.3", "1.15.2"))
+		gb.registryPath = serveArchives(t, gb, "1.16.13", "1.17.6").URL + "/"
+		installFakeVersion(t, gb, "1.15.2", "go version go1.15.2 linux/amd64")
+		installFakeVersion(t, gb, "1.16.3", "go version go1.16.3 linux/amd64")
+		installFakeVersion(t, gb, "1.17.1", "go version go1.17.1 linux/amd64")
+		useFixture(t, gb, "1.17.1")
+		if remove {
+			gb.EnableRemoveSuperseded()
+		}
+
+		if err := gb.UpgradeAll(); err != nil {
+			t.Fatal(err)
+		}
+
+		want := []string{"1.15.2", "1.16.13", "1.16.3", "1.17.1", "1.17.6"}
+		wantCurrent := "1.17.1"
+		if remove {
+			want = []string{"1.15.2", "1.16.13", "1.17.6"}
+			wantCurrent = "1.17.6"
+		}
+		installed, err := gb.InstalledVersions()
+		if err != nil {
+			t.Fatal(err)
+		}
+		sort.Strings(installed)
+		if !reflect.DeepEqual(installed, want) {
+			t.Errorf("remove %v: installed %v, want %v", remove, installed, want)
+		}
+		if cv := gb.CurrentVersion(); cv != wantCurrent {
+			t.Errorf("remove %v: current %q, want %q", remove, cv, wantCurrent)
+		}
+	}
+}
+
+func TestUpgradeAllDropsReferences(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	serveReleases(t, &gb, hostReleases("1.16.13", "1.16.3"))
+	gb.registryPath = serveArchives(t, gb, "1.16.13").URL + "/"
+	installFakeVersion(t, gb, "1.16.3", "go version go1.16.3 linux/amd64")
+	if err := gb.Alias("work", "1.16.3"); err != nil {
+		t.Fatal(err)
+	}
+	gb.EnableRemoveSuperseded()
+
+	if err := gb.UpgradeAll(); err != nil {
+		t.Fatal(err)
+	}
+	if gb.existsVersion("1.16.3") {
+		t.Fatal("expected 1.16.3 removed")
+	}
+	aliases, err := gb.Aliases()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if target, ok := aliases["work"]; ok {
+		t.Errorf("expected the alias on the removed 1.16.3 dropped, it points at %s", target)
+	}
+}
+
+func TestOutdated(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	serveReleases(t, &gb, hostReleases("1.18beta1", "1.17.6", "1.17.1", "1.16.13", "1.16.3"))
+	// 1.16 is behind, 1.17 is on its latest patch, 1.15 is no longer released
+	installFakeVersion(t, gb, "1.15.2", "go version go1.15.2 linux/amd64")
+	installFakeVersion(t, gb, "1.16.1", "go version go1.16.1 linux/amd64")
+	installFakeVersion(t, gb, "1.16.3", "go version go1.16.3 linux/amd64")
+	installFakeVersion(t, gb, "1.17.6", "go version go1.17.6 linux/amd64")
+
+	outdated, err := gb.Outdated()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []OutdatedInfo{{Minor: "1.16", Installed: "1.16.3", Latest: "1.16.13"}}
+	if !reflect.DeepEqual(outdated, want) {
+		t.Errorf("expected %+v, got %+v", want, outdated)
+	}
+}
+
+func TestUpgradeAllSummary(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	serveReleases(t, &gb, hostReleases("1.17.6", "1.16.13"))
+	gb.registryPath = serveArchives(t, gb, "1.16.13", "1.17.6").URL + "/"
+	installFakeVersion(t, gb, "1.16.3", "go version go1.16.3 linux/amd64")
+	installFakeVersion(t, gb, "1.17.1", "go version go1.17.1 linux/amd64")
+	var out bytes.Buffer
+	gb.stdout = &out
+
+	if err := gb.UpgradeAll(); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"Upgraded 1.16.3 -> 1.16.13", "Upgraded 1.17.1 -> 1.17.6"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("expected %q in the summary, got:\n%s", line, out.String())
+		}
+	}
+
+	out.Reset()
+	if err := gb.UpgradeAll(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out.String(), "Upgraded") {
+		t.Errorf("expected nothing left to upgrade, got:\n%s", out.String())
+	}
+}
@@ -2,9 +2,11 @@ package utils
 
 import (
 	"bufio"
+	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
 
 	"github.com/fatih/color"
 )
@@ -14,39 +16,51 @@ var ColorSuccess = color.New(color.FgHiGreen)
 var ColorInfo = color.New(color.FgHiYellow)
 var ColorError = color.New(color.FgHiRed)
 
-// Download resource from url to a destination path
+// maxRedirects a download follows, e.g. golang.org/dl to go.dev/dl to a mirror
+const maxRedirects = 10
+
+var downloadClient = &http.Client{
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		return nil
+	},
+}
+
+// Download resource from url to a destination path, following redirects.
+// Any status but 2xx is an error, leaving no file at the destination
 func Download(url string, filepath string) (err error) {
-	resp, err := http.Get(url)
+	resp, err := downloadClient.Get(url)
 	if err != nil {
 		ColorError.Printf("[Error]: http get file: %s \n", url)
 		return err
 	}
+	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
 		ColorError.Printf("[Error]: Response status code: %d \n", resp.StatusCode)
-		ColorInfo.Printf("[Info]: Please wait for the file to download.\n")
-	} else {
-		ColorSuccess.Printf("[Success]: Response status code: %d \n", resp.StatusCode)
+		return fmt.Errorf("downloading %s: %s", url, resp.Status)
 	}
-
-	defer resp.Body.Close()
+	ColorSuccess.Printf("[Success]: Response status code: %d \n", resp.StatusCode)
 
 	out, err := os.Create(filepath)
-	wt := bufio.NewWriter(out)
-
 	if err != nil {
 		ColorError.Printf("[Error]: Creating file: %s \n", err.Error())
 		return err
 	}
-
 	defer out.Close()
 
+	wt := bufio.NewWriter(out)
 	_, err = io.Copy(wt, resp.Body)
-
+	if err == nil {
+		err = wt.Flush()
+	}
 	if err != nil {
+		out.Close()
+		os.Remove(filepath)
 		return err
 	}
-	wt.Flush()
 	return nil
 }
 
@@ -63,3 +77,32 @@ func Find(slice []string, val string) bool {
 	}
 	return false
 }
+
+// DirSize sums the size of every regular file under path, without following symlinks
+func DirSize(path string) (int64, error) {
+	var size int64
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// HumanSize formats a byte count, e.g. 1536 -> 1.5 KB
+func HumanSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
+}
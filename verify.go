@@ -0,0 +1,131 @@
+package gobrew
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/kevincobain2000/gobrew/utils"
+)
+
+// keyBinaries that every installed toolchain must ship in go/bin
+var keyBinaries = []string{"go", "gofmt"}
+
+// goCommandTimeout bounds running a go binary to ask its version, a broken
+// one may hang. Swapped out by tests
+var goCommandTimeout = 30 * time.Second
+
+// runGo runs goBin with args, killing it after goCommandTimeout. It runs with
+// GOTOOLCHAIN=local from the temp dir, so neither the user's GOTOOLCHAIN nor a
+// go.mod in the working dir switches to, or downloads, another toolchain
+func runGo(goBin string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), goCommandTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, goBin, args...)
+	cmd.Env = mergeEnv(os.Environ(), "GOTOOLCHAIN=local")
+	cmd.Dir = os.TempDir()
+	output, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return output, fmt.Errorf("%w: %s %s was killed after %s", ErrTimeout, goBin, strings.Join(args, " "), goCommandTimeout)
+	}
+	return output, err
+}
+
+// exeName appends the executable suffix of the host platform
+func exeName(name string) string {
+	if runtime.GOOS == "windows" {
+		return name + ".exe"
+	}
+	return name
+}
+
+// EnableVerifyExisting makes Install run Verify on a version that is already
+// installed, reinstalling it when the check fails
+func (gb *GoBrew) EnableVerifyExisting() {
+	gb.verifyExisting = true
+}
+
+// Verify checks an installed version is intact: its files match the manifest,
+// its key binaries are present and `go version` still runs
+func (gb *GoBrew) Verify(version string) error {
+	if !gb.existsVersion(version) {
+		return fmt.Errorf("version %s is not installed", version)
+	}
+	if err := gb.VerifyManifest(version); err != nil {
+		return err
+	}
+	binDir := gb.versionBinDir(version)
+	for _, name := range keyBinaries {
+		if _, err := os.Stat(filepath.Join(binDir, exeName(name))); err != nil {
+			return fmt.Errorf("version %s is corrupt: %s", version, err)
+		}
+	}
+
+	output, err := runGo(filepath.Join(binDir, exeName("go")), "version")
+	if err != nil {
+		return fmt.Errorf("version %s is corrupt: go version failed: %w: %s", version, err, strings.TrimSpace(utils.BytesToString(output)))
+	}
+	return nil
+}
+
+// CheckSymlinkConsistency makes sure currentBinDir and currentGoDir belong to
+// the same version, which a Use interrupted between the two links breaks.
+// Having no current version is consistent
+func (gb *GoBrew) CheckSymlinkConsistency() error {
+	if _, err := os.Lstat(gb.currentBinDir); os.IsNotExist(err) {
+		return nil
+	}
+	bin, err := filepath.EvalSymlinks(gb.currentBinDir)
+	if err != nil {
+		return fmt.Errorf("cannot resolve %s: %s", gb.currentBinDir, err)
+	}
+	goDir, err := filepath.EvalSymlinks(gb.currentGoDir)
+	if err != nil {
+		return fmt.Errorf("cannot resolve %s: %s", gb.currentGoDir, err)
+	}
+	if filepath.Dir(bin) != goDir {
+		return fmt.Errorf("%s points to %s but %s points to %s, use a version again to relink both", gb.currentBinDir, bin, gb.currentGoDir, goDir)
+	}
+	return nil
+}
+
+// checkReportedVersion makes sure the go of an installed version reports that
+// version, catching a mirror serving the wrong archive. A go that doesn't run
+// is left to Verify
+func (gb *GoBrew) checkReportedVersion(version string) error {
+	goBin := filepath.Join(gb.versionBinDir(version), exeName("go"))
+	output, err := runGo(goBin, "version")
+	if err != nil {
+		gb.debug.Printf("not checking the version of %s: %s", version, err)
+		return nil
+	}
+	reported, err := parseGoVersionOutput(utils.BytesToString(output))
+	if err != nil {
+		gb.debug.Printf("not checking the version of %s: %s", version, err)
+		return nil
+	}
+	if reported != version {
+		return fmt.Errorf("version %s reports go%s, the archive is for another version", version, reported)
+	}
+	return nil
+}
+
+// VerifyAll verifies every installed version, returning the failures by version
+func (gb *GoBrew) VerifyAll() (map[string]error, error) {
+	versions, err := gb.InstalledVersions()
+	if err != nil {
+		return nil, err
+	}
+	failures := make(map[string]error)
+	for _, version := range versions {
+		if err := gb.Verify(version); err != nil {
+			failures[version] = err
+		}
+	}
+	return failures, nil
+}
@@ -0,0 +1,182 @@
+package gobrew
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// installFakeVersion lays out versionsDir/<version>/go/bin with shell scripts
+// standing in for the go toolchain; the go script prints goVersionOutput.
+func installFakeVersion(t *testing.T, gb GoBrew, version string, goVersionOutput string) {
+	t.Helper()
+	binDir := filepath.Join(gb.getVersionDir(version), "go", "bin")
+	if err := os.MkdirAll(binDir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	scripts := map[string]string{
+		"go":    "#!/bin/sh\necho '" + goVersionOutput + "'\n",
+		"gofmt": "#!/bin/sh\n",
+	}
+	for name, script := range scripts {
+		if err := ioutil.WriteFile(filepath.Join(binDir, name), []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	installFakeVersion(t, gb, "1.16", "go version go1.16 linux/amd64")
+	installFakeVersion(t, gb, "1.17", "go version go1.17 linux/amd64")
+	os.Remove(filepath.Join(gb.getVersionDir("1.17"), "go", "bin", "gofmt"))
+
+	if err := gb.Verify("1.16"); err != nil {
+		t.Errorf("1.16 should verify: %s", err)
+	}
+	if err := gb.Verify("1.17"); err == nil {
+		t.Error("1.17 is missing gofmt and should fail verification")
+	}
+
+	failures, err := gb.VerifyAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(failures) != 1 || failures["1.17"] == nil {
+		t.Errorf("expected only 1.17 to fail, got %v", failures)
+	}
+}
+
+func TestInstallVerifyExistingRepairs(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	gb.registryPath = serveArchives(t, gb, "1.16").URL + "/"
+	installFakeVersion(t, gb, "1.16", "go version go1.16 linux/amd64")
+	os.Remove(filepath.Join(gb.getVersionDir("1.16"), "go", "bin", "gofmt"))
+
+	gb.Install("1.16")
+	if err := gb.Verify("1.16"); err == nil {
+		t.Fatal("without the option an existing install should be trusted as is")
+	}
+
+	gb.EnableVerifyExisting()
+	gb.Install("1.16")
+	if err := gb.Verify("1.16"); err != nil {
+		t.Errorf("expected the corrupt install repaired: %s", err)
+	}
+}
+
+func TestCheckReportedVersion(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	installFakeVersion(t, gb, "1.16", "go version go1.16 linux/amd64")
+	installFakeVersion(t, gb, "1.17", "go version go1.16 linux/amd64")
+	installFakeVersion(t, gb, "1.22rc1", "go version go1.22rc1 linux/amd64")
+	installFakeVersion(t, gb, "1.18", "not a go")
+
+	for _, version := range []string{"1.16", "1.22rc1", "1.18"} {
+		if err := gb.checkReportedVersion(version); err != nil {
+			t.Errorf("%s: expected no error, got %v", version, err)
+		}
+	}
+	err := gb.checkReportedVersion("1.17")
+	if err == nil || !strings.Contains(err.Error(), "reports go1.16") {
+		t.Errorf("expected a mismatch for 1.17, got %v", err)
+	}
+}
+
+func TestCheckSymlinkConsistency(t *testing.T) {
+	gb := newTestGoBrew(tempDir(t))
+	if err := gb.CheckSymlinkConsistency(); err != nil {
+		t.Errorf("no current version should be consistent: %s", err)
+	}
+	installFakeVersion(t, gb, "1.16", "go version go1.16 linux/amd64")
+	installFakeVersion(t, gb, "1.17", "go version go1.17 linux/amd64")
+	useFixture(t, gb, "1.16")
+	if err := gb.CheckSymlinkConsistency(); err != nil {
+		t.Errorf("expected consistent links: %s", err)
+	}
+
+	// bin switched to 1.17, go left behind on 1.16
+	os.Remove(gb.currentBinDir)
+	os.Symlink(filepath.Join(gb.getVersionDir("1.17"), "go", "bin"), gb.currentBinDir)
+	err := gb.CheckSymlinkConsistency()
+	if err == nil {
+		t.Fatal("expected an error for links to different versions")
+	}
+	if !strings.Contains(err.Error(), "1.16") || !strings.Contains(err.Error(), "1.17") {
+		t.Errorf("expected both versions named, got %s", err)
+	}
+
+	diagnostics, _ := gb.Doctor()
+	found := false
+	for _, d := range diagnostics {
+		if d.Name != "symlinks" {
+			continue
+		}
+		found = true
+		if d.Severity != SeverityError || d.Fix != "gobrew use 1.17" {
+			t.Errorf("unexpected diagnostic %+v", d)
+		}
+	}
+	if !found {
+		t.Error("expected Doctor to check the symlinks")
+	}
+
+	os.Remove(gb.currentGoDir)
+	if err := gb.CheckSymlinkConsistency(); err == nil {
+		t.Error("expected an error for a missing go link")
+	}
+}
+
+func TestVerifyKillsHangingGo(t *testing.T) {
+	defer func(timeout time.Duration) { goCommandTimeout = timeout }(goCommandTimeout)
+	goCommandTimeout = 100 * time.Millisecond
+	gb := newTestGoBrew(tempDir(t))
+	installFakeVersion(t, gb, "1.16", "")
+	pidFile := filepath.Join(tempDir(t), "pid")
+	ioutil.WriteFile(filepath.Join(gb.getVersionDir("1.16"), "go", "bin", "go"), []byte("#!/bin/sh\necho $$ > "+pidFile+"\nexec sleep 30\n"), 0755)
+
+	start := time.Now()
+	err := gb.Verify("1.16")
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("expected ErrTimeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Verify took %s, the timeout didn't fire", elapsed)
+	}
+	content, err := ioutil.ReadFile(pidFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid, _ := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err := syscall.Kill(pid, 0); err == nil {
+		t.Errorf("expected the hanging go (pid %d) to be killed", pid)
+	}
+}
+
+func TestRunGoIgnoresToolchainSwitch(t *testing.T) {
+	os.Setenv("GOTOOLCHAIN", "go1.99.0")
+	defer os.Unsetenv("GOTOOLCHAIN")
+	gb := newTestGoBrew(tempDir(t))
+	installFakeVersion(t, gb, "1.16", "")
+	goBin := filepath.Join(gb.getVersionDir("1.16"), "go", "bin", "go")
+	ioutil.WriteFile(goBin, []byte("#!/bin/sh\necho \"$GOTOOLCHAIN $(pwd)\"\n"), 0755)
+
+	wd, _ := os.Getwd()
+	output, err := runGo(goBin, "version")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Fields(string(output))
+	if len(got) != 2 || got[0] != "local" {
+		t.Errorf("expected GOTOOLCHAIN=local, got %q", output)
+	}
+	if len(got) == 2 && got[1] == wd {
+		t.Errorf("expected go to run outside the working dir %s", wd)
+	}
+}
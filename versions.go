@@ -0,0 +1,105 @@
+package gobrew
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// reGoVersion matches go release names: 1.16, 1.16.3, 1.18beta1, 1.8.5rc4
+var reGoVersion = regexp.MustCompile(`^(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:(beta|rc)(\d+))?$`)
+
+// goVersion is a parsed go release name
+type goVersion struct {
+	major, minor, patch int
+	// pre is "beta" or "rc" for prereleases, empty for releases
+	pre    string
+	preNum int
+}
+
+// parseGoVersion parses a go release name like 1.16.3 or 1.18rc1
+func parseGoVersion(version string) (goVersion, bool) {
+	m := reGoVersion.FindStringSubmatch(version)
+	if m == nil {
+		return goVersion{}, false
+	}
+	atoi := func(s string) int {
+		n, _ := strconv.Atoi(s)
+		return n
+	}
+	return goVersion{
+		major:  atoi(m[1]),
+		minor:  atoi(m[2]),
+		patch:  atoi(m[3]),
+		pre:    m[4],
+		preNum: atoi(m[5]),
+	}, true
+}
+
+// preRank orders beta before rc before the release itself
+func (v goVersion) preRank() int {
+	switch v.pre {
+	case "beta":
+		return 0
+	case "rc":
+		return 1
+	}
+	return 2
+}
+
+// compareVersions returns -1, 0 or 1 as go release a sorts before, with or
+// after b. Names that don't parse sort after every release, by name.
+func compareVersions(a, b string) int {
+	va, okA := parseGoVersion(a)
+	vb, okB := parseGoVersion(b)
+	switch {
+	case !okA && !okB:
+		return strings.Compare(a, b)
+	case !okA:
+		return 1
+	case !okB:
+		return -1
+	}
+
+	for _, d := range []int{
+		va.major - vb.major,
+		va.minor - vb.minor,
+		va.patch - vb.patch,
+		va.preRank() - vb.preRank(),
+		va.preNum - vb.preNum,
+	} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+	return 0
+}
+
+// sortVersions sorts go release names in place, oldest first
+func sortVersions(versions []string) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		return compareVersions(versions[i], versions[j]) < 0
+	})
+}
+
+// reLoosePre matches a prerelease suffix written with a separator, 1.22-rc1 or 1.22.rc1
+var reLoosePre = regexp.MustCompile(`[.-](beta|rc)(\d+)$`)
+
+// normalizeVersion turns the ways users write a go version into its release
+// name: go1.22 and v1.22 become 1.22, 1.22-rc1 becomes 1.22rc1. Prereleases
+// are only ever installed when named explicitly like this.
+func normalizeVersion(version string) (string, error) {
+	normalized := strings.TrimSpace(version)
+	normalized = strings.TrimPrefix(normalized, "go")
+	normalized = strings.TrimPrefix(normalized, "v")
+	normalized = reLoosePre.ReplaceAllString(normalized, "$1$2")
+	if !reGoVersion.MatchString(normalized) {
+		return "", fmt.Errorf("invalid go version %q", version)
+	}
+	return normalized, nil
+}
@@ -0,0 +1,39 @@
+package gobrew
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortVersions(t *testing.T) {
+	versions := []string{"1.10", "1.9", "1.10beta1", "1.10rc2", "1.10.1", "1.10rc1", "1.8.5rc4", "1.8.5", "tip"}
+	sortVersions(versions)
+	want := []string{"1.8.5rc4", "1.8.5", "1.9", "1.10beta1", "1.10rc1", "1.10rc2", "1.10", "1.10.1", "tip"}
+	if !reflect.DeepEqual(versions, want) {
+		t.Errorf("got %v, want %v", versions, want)
+	}
+}
+
+func TestNormalizeVersion(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "1.22rc1", want: "1.22rc1"},
+		{in: "1.22beta1", want: "1.22beta1"},
+		{in: "go1.22rc1", want: "1.22rc1"},
+		{in: "1.22-rc1", want: "1.22rc1"},
+		{in: "1.22.rc2", want: "1.22rc2"},
+		{in: "v1.17.6", want: "1.17.6"},
+		{in: " 1.16 ", want: "1.16"},
+		{in: "1.22rc", wantErr: true},
+		{in: "latest", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := normalizeVersion(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("normalizeVersion(%q) = (%q, %v), want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
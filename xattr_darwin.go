@@ -0,0 +1,27 @@
+//go:build darwin
+// +build darwin
+
+package gobrew
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// quarantineXattr makes Gatekeeper block a binary, it is never restored
+const quarantineXattr = "com.apple.quarantine"
+
+// setXattrs restores the extended attributes of an extracted file, such as
+// code signing metadata, so the go binary isn't flagged by Gatekeeper
+func setXattrs(target string, xattrs map[string]string) error {
+	for name, value := range xattrs {
+		if name == quarantineXattr {
+			continue
+		}
+		if err := unix.Setxattr(target, name, []byte(value), 0); err != nil {
+			return &os.PathError{Op: "setxattr " + name, Path: target, Err: err}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+//go:build darwin
+// +build darwin
+
+package gobrew
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestExtractXattrs(t *testing.T) {
+	archive := filepath.Join(tempDir(t), "go.tar.gz")
+	f, err := os.Create(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+	script := "#!/bin/sh\necho 'go version go1.21.0 darwin/arm64'\n"
+	hdr := &tar.Header{
+		Name: "go/bin/go", Mode: 0755, Size: int64(len(script)), Typeflag: tar.TypeReg, Format: tar.FormatPAX,
+		PAXRecords: map[string]string{
+			paxXattrPrefix + "com.example.signed": "yes",
+			paxXattrPrefix + quarantineXattr:      "0081;00000000;Safari;",
+		},
+	}
+	tw.WriteHeader(hdr)
+	tw.Write([]byte(script))
+	tw.Close()
+	gw.Close()
+	f.Close()
+
+	gb := newTestGoBrew(tempDir(t))
+	dest := tempDir(t)
+	if err := gb.extract(archive, dest, 1); err != nil {
+		t.Fatal(err)
+	}
+	goBin := filepath.Join(dest, "go", "bin", "go")
+
+	buf := make([]byte, 64)
+	n, err := unix.Getxattr(goBin, "com.example.signed", buf)
+	if err != nil || string(buf[:n]) != "yes" {
+		t.Errorf("expected the xattr restored, got (%q, %v)", buf[:n], err)
+	}
+	if _, err := unix.Getxattr(goBin, quarantineXattr, buf); err == nil {
+		t.Error("expected the quarantine xattr dropped")
+	}
+	output, err := exec.Command(goBin, "version").CombinedOutput()
+	if err != nil || !strings.Contains(string(output), "go1.21.0") {
+		t.Errorf("expected the extracted go to run, got (%s, %v)", output, err)
+	}
+}
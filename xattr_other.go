@@ -0,0 +1,9 @@
+//go:build !darwin
+// +build !darwin
+
+package gobrew
+
+// setXattrs is only needed on darwin, where Gatekeeper checks them
+func setXattrs(target string, xattrs map[string]string) error {
+	return nil
+}